@@ -14,11 +14,14 @@ require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/gomodule/redigo v2.0.1-0.20180401191855-9352ab68be13+incompatible
 	github.com/jinzhu/gorm v0.0.0-20180213101209-6e1387b44c64
+	github.com/mozillazg/go-pinyin v0.21.0
 	github.com/swaggo/gin-swagger v1.2.0
 	github.com/swaggo/swag v1.8.12
 	github.com/tealeg/xlsx v1.0.4-0.20180419195153-f36fa3be8893
 	github.com/unknwon/com v1.0.1
 	golang.org/x/crypto v0.39.0
+	golang.org/x/net v0.40.0
+	golang.org/x/text v0.26.0
 )
 
 require (
@@ -49,9 +52,7 @@ require (
 	github.com/ugorji/go/codec v1.1.5-pre // indirect
 	go.uber.org/atomic v1.11.0 // indirect
 	golang.org/x/image v0.0.0-20180628062038-cc896f830ced // indirect
-	golang.org/x/net v0.40.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.26.0 // indirect
 	golang.org/x/tools v0.33.0 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
 	gopkg.in/go-playground/validator.v8 v8.18.2 // indirect
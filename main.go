@@ -4,15 +4,23 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/EDDYCJY/go-gin-example/models"
 	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
 	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/mail"
+	"github.com/EDDYCJY/go-gin-example/pkg/pwpolicy"
 	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/slug"
 	"github.com/EDDYCJY/go-gin-example/routers"
 	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/search_service"
+	"github.com/EDDYCJY/go-gin-example/service/trash_service"
+	"github.com/EDDYCJY/go-gin-example/service/view_service"
 )
 
 func init() {
@@ -21,6 +29,11 @@ func init() {
 	logging.Setup()
 	gredis.Setup()
 	util.Setup()
+	mail.Setup()
+	pwpolicy.Setup()
+	slug.Setup()
+	search_service.Setup()
+	view_service.Setup()
 }
 
 // @title Golang Gin API
@@ -33,9 +46,17 @@ func init() {
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token. Use /auth endpoint to get token.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key created with POST /auth/api-keys, accepted instead of a Bearer token on /api/v1.
 func main() {
 	gin.SetMode(setting.ServerSetting.RunMode)
 
+	article_service.StartScheduler(setting.AppSetting.PublishSchedulerInterval)
+	trash_service.StartRetentionJob(time.Duration(setting.AppSetting.TrashRetentionDays)*24*time.Hour, setting.AppSetting.TrashPurgeInterval)
+	view_service.StartFlushJob(setting.AppSetting.ViewFlushInterval)
+
 	routersInit := routers.InitRouter()
 	readTimeout := setting.ServerSetting.ReadTimeout
 	writeTimeout := setting.ServerSetting.WriteTimeout
@@ -0,0 +1,43 @@
+package jwt
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/api_key_service"
+	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+)
+
+// parseApiKey authenticates an API key, returning claims for its owner restricted to its scopes
+func parseApiKey(key string) (*util.Claims, int) {
+	apiKey, err := api_key_service.Authenticate(key)
+	switch err {
+	case nil:
+	case api_key_service.ErrApiKeyInvalid:
+		return nil, e.ERROR_AUTH_API_KEY_INVALID
+	case api_key_service.ErrApiKeyExpired:
+		return nil, e.ERROR_AUTH_API_KEY_EXPIRED
+	default:
+		logging.Warn(err)
+		return nil, e.ERROR_AUTH_CHECK_TOKEN_FAIL
+	}
+
+	// The role is read on every request, so role changes and disabled accounts apply at once
+	authService := auth_service.Auth{ID: apiKey.AuthID}
+	user, err := authService.Get()
+	if err != nil {
+		logging.Warn(err)
+		return nil, e.ERROR_AUTH_CHECK_TOKEN_FAIL
+	}
+	if user.ID == 0 || user.Status != models.AUTH_STATUS_ACTIVE {
+		return nil, e.ERROR_AUTH_API_KEY_INVALID
+	}
+
+	return &util.Claims{
+		Username: user.Username,
+		Role:     user.Role,
+		ApiKeyID: apiKey.ID,
+		Scopes:   api_key_service.GetScopes(apiKey),
+	}, e.SUCCESS
+}
@@ -9,6 +9,14 @@ import (
 
 	"github.com/EDDYCJY/go-gin-example/pkg/e"
 	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+const (
+	// CLAIMS_KEY is the gin context key holding the parsed *util.Claims
+	CLAIMS_KEY = "claims"
+	// API_KEY_HEADER is the request header carrying an API key
+	API_KEY_HEADER = "X-API-Key"
 )
 
 // JWT is jwt middleware
@@ -16,12 +24,14 @@ func JWT() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var code int
 		var data interface{}
+		var claims *util.Claims
 
 		code = e.SUCCESS
 		
-		// Get token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		// API keys are an alternative to the Authorization header for machine clients
+		if apiKey := c.GetHeader(API_KEY_HEADER); apiKey != "" {
+			claims, code = parseApiKey(apiKey)
+		} else if authHeader := c.GetHeader("Authorization"); authHeader == "" {
 			code = e.INVALID_PARAMS
 		} else {
 			// Check if header starts with "Bearer "
@@ -31,7 +41,8 @@ func JWT() gin.HandlerFunc {
 				if token == "" {
 					code = e.INVALID_PARAMS
 				} else {
-					_, err := util.ParseToken(token)
+					var err error
+					claims, err = util.ParseToken(token)
 					if err != nil {
 						// Check if it's a JWT validation error
 						if ve, ok := err.(*jwt.ValidationError); ok {
@@ -41,8 +52,10 @@ func JWT() gin.HandlerFunc {
 							default:
 								code = e.ERROR_AUTH_CHECK_TOKEN_FAIL
 							}
+						} else if err == jwt_redis_service.ErrStoreUnavailable {
+							code = e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE
 						} else {
-							// Handle other types of errors (Redis, etc.)
+							// Handle other types of errors
 							code = e.ERROR_AUTH_CHECK_TOKEN_FAIL
 						}
 					}
@@ -54,7 +67,12 @@ func JWT() gin.HandlerFunc {
 		}
 
 		if code != e.SUCCESS {
-			c.JSON(http.StatusUnauthorized, gin.H{
+			httpCode := http.StatusUnauthorized
+			if code == e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE {
+				httpCode = http.StatusServiceUnavailable
+			}
+
+			c.JSON(httpCode, gin.H{
 				"code": code,
 				"msg":  e.GetMsg(code),
 				"data": data,
@@ -64,6 +82,38 @@ func JWT() gin.HandlerFunc {
 			return
 		}
 
+		c.Set(CLAIMS_KEY, claims)
+		c.Next()
+	}
+}
+
+// SessionOnly rejects requests authenticated with an API key or an OAuth2 client token, it must
+// run after JWT(). It guards credentials and session management, which a leaked key must not be
+// able to touch.
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims := GetClaims(c); claims != nil && (claims.ApiKeyID != 0 || claims.ClientID != "") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code": e.ERROR_AUTH_SESSION_REQUIRED,
+				"msg":  e.GetMsg(e.ERROR_AUTH_SESSION_REQUIRED),
+				"data": nil,
+			})
+
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
+
+// GetClaims returns the claims stored by the JWT middleware
+func GetClaims(c *gin.Context) *util.Claims {
+	if v, ok := c.Get(CLAIMS_KEY); ok {
+		if claims, ok := v.(*util.Claims); ok {
+			return claims
+		}
+	}
+
+	return nil
+}
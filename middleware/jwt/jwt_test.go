@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis/gredistest"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+func setup(t *testing.T, policy string) *gredistest.Server {
+	gin.SetMode(gin.TestMode)
+	old := *setting.AppSetting
+	setting.AppSetting.JwtSecret = "test-secret"
+	setting.AppSetting.JwtSigningMethod = "HS256"
+	setting.AppSetting.AccessTokenExpire = 15 * time.Minute
+	setting.AppSetting.SessionStoreFailurePolicy = policy
+	t.Cleanup(func() {
+		*setting.AppSetting = old
+	})
+	util.Setup()
+
+	return gredistest.Use(t)
+}
+
+// request runs a request with the authorization header through JWT(), returning the status and code
+func request(t *testing.T, authorization string) (int, int) {
+	r := gin.New()
+	r.GET("/", JWT(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": e.SUCCESS})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var body struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %q: %v", w.Body.String(), err)
+	}
+
+	return w.Code, body.Code
+}
+
+func TestJWT(t *testing.T) {
+	setup(t, jwt_redis_service.FAILURE_POLICY_CLOSED)
+	token, err := util.GenerateToken("alice", "admin", "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantCode      int
+	}{
+		{"missing header", "", http.StatusUnauthorized, e.INVALID_PARAMS},
+		{"not bearer", "Basic " + token, http.StatusUnauthorized, e.INVALID_PARAMS},
+		{"malformed token", "Bearer not-a-token", http.StatusUnauthorized, e.ERROR_AUTH_CHECK_TOKEN_FAIL},
+		{"valid token", "Bearer " + token, http.StatusOK, e.SUCCESS},
+	}
+	for _, tt := range tests {
+		status, code := request(t, tt.authorization)
+		if status != tt.wantStatus || code != tt.wantCode {
+			t.Errorf("%s: got %d %d, want %d %d", tt.name, status, code, tt.wantStatus, tt.wantCode)
+		}
+	}
+}
+
+func TestJWTRevokedToken(t *testing.T) {
+	setup(t, jwt_redis_service.FAILURE_POLICY_CLOSED)
+	token, err := util.GenerateToken("alice", "admin", "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	claims, err := util.ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	if _, err := jwt_redis_service.DeleteSession("alice", claims.Id); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+
+	if status, code := request(t, "Bearer "+token); status != http.StatusUnauthorized || code != e.ERROR_AUTH_CHECK_TOKEN_FAIL {
+		t.Errorf("got %d %d, want %d %d", status, code, http.StatusUnauthorized, e.ERROR_AUTH_CHECK_TOKEN_FAIL)
+	}
+}
+
+func TestJWTSessionStoreDown(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantStatus int
+		wantCode   int
+	}{
+		{jwt_redis_service.FAILURE_POLICY_OPEN, http.StatusOK, e.SUCCESS},
+		{jwt_redis_service.FAILURE_POLICY_CLOSED, http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE},
+		{jwt_redis_service.FAILURE_POLICY_LOCAL, http.StatusOK, e.SUCCESS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			redis := setup(t, tt.policy)
+			token, err := util.GenerateToken("alice", "admin", "test", "127.0.0.1")
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			redis.SetDown(true)
+
+			if status, code := request(t, "Bearer "+token); status != tt.wantStatus || code != tt.wantCode {
+				t.Errorf("got %d %d, want %d %d", status, code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJWTLocalRevocationWhileDown(t *testing.T) {
+	redis := setup(t, jwt_redis_service.FAILURE_POLICY_LOCAL)
+	token, err := util.GenerateToken("alice", "admin", "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	claims, err := util.ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	redis.SetDown(true)
+
+	if err := jwt_redis_service.RevokeToken(claims.Username, claims.Id, claims.Family, claims.ExpiresAt); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if status, code := request(t, "Bearer "+token); status != http.StatusUnauthorized || code != e.ERROR_AUTH_CHECK_TOKEN_FAIL {
+		t.Errorf("got %d %d, want %d %d", status, code, http.StatusUnauthorized, e.ERROR_AUTH_CHECK_TOKEN_FAIL)
+	}
+}
@@ -0,0 +1,47 @@
+package permission
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+)
+
+// Require is permission middleware, it must run after jwt.JWT()
+func Require(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		code := e.SUCCESS
+
+		claims := jwt.GetClaims(c)
+		if claims == nil {
+			code = e.ERROR_AUTH_CHECK_TOKEN_FAIL
+		} else {
+			for _, p := range permissions {
+				if !claims.HasPermission(p) {
+					code = e.ERROR_AUTH_PERMISSION_DENIED
+					break
+				}
+			}
+		}
+
+		if code != e.SUCCESS {
+			httpCode := http.StatusForbidden
+			if code == e.ERROR_AUTH_CHECK_TOKEN_FAIL {
+				httpCode = http.StatusUnauthorized
+			}
+
+			c.JSON(httpCode, gin.H{
+				"code": code,
+				"msg":  e.GetMsg(code),
+				"data": nil,
+			})
+
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_auth_password_history`;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `blog_auth_password_history` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `auth_id` int(10) unsigned NOT NULL COMMENT '所属用户ID',
+  `password` varchar(60) NOT NULL COMMENT '密码哈希',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '设置时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_auth_id` (`auth_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='用户历史密码';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_auth_event`;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `blog_auth_event` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(100) DEFAULT '' COMMENT '用户名或客户端',
+  `event` varchar(32) NOT NULL COMMENT '事件类型',
+  `ip` varchar(45) DEFAULT '' COMMENT '客户端IP',
+  `user_agent` varchar(255) DEFAULT '' COMMENT '客户端UA',
+  `detail` varchar(255) DEFAULT '' COMMENT '详情',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '发生时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_username_created_on` (`username`,`created_on`),
+  KEY `idx_ip_created_on` (`ip`,`created_on`),
+  KEY `idx_created_on` (`created_on`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='认证审计日志';
\ No newline at end of file
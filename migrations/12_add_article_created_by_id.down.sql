@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  DROP KEY `idx_created_by_id`,
+  DROP COLUMN `created_by_id`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `created_by_id` int(10) unsigned DEFAULT '0' COMMENT '创建人ID' AFTER `created_by`,
+  ADD KEY `idx_created_by_id` (`created_by_id`);
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `blog_article` SET `created_by_id` = 0;
\ No newline at end of file
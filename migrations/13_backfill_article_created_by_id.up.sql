@@ -0,0 +1,4 @@
+UPDATE `blog_article` a
+  INNER JOIN `blog_auth` u ON u.`username` = a.`created_by`
+  SET a.`created_by_id` = u.`id`
+  WHERE a.`created_by_id` = 0;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_article_author`;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `blog_article_author` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章ID',
+  `auth_id` int(10) unsigned NOT NULL COMMENT '用户ID',
+  `role` varchar(20) NOT NULL DEFAULT 'viewer' COMMENT '文章权限 owner、editor、viewer',
+  `created_by` varchar(100) DEFAULT '' COMMENT '添加人',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '添加时间',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_article_auth` (`article_id`,`auth_id`),
+  KEY `idx_auth_id` (`auth_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章协作者';
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS `blog_article_tag` (
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章ID',
+  `tag_id` int(10) unsigned NOT NULL COMMENT '标签ID',
+  PRIMARY KEY (`article_id`,`tag_id`),
+  KEY `idx_tag_id` (`tag_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章标签关联';
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM `blog_article_tag`;
\ No newline at end of file
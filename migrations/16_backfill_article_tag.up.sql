@@ -0,0 +1,2 @@
+INSERT IGNORE INTO `blog_article_tag` (`article_id`, `tag_id`)
+  SELECT `id`, `tag_id` FROM `blog_article` WHERE `tag_id` > 0;
\ No newline at end of file
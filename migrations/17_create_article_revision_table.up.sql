@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `blog_article_revision` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章ID',
+  `revision` int(10) unsigned NOT NULL COMMENT '版本号',
+  `tag_ids` varchar(255) DEFAULT '' COMMENT '标签ID列表',
+  `title` varchar(100) DEFAULT '' COMMENT '文章标题',
+  `desc` varchar(255) DEFAULT '' COMMENT '简述',
+  `content` text COMMENT '内容',
+  `cover_image_url` varchar(255) DEFAULT '' COMMENT '封面图片地址',
+  `state` tinyint(3) unsigned DEFAULT '1' COMMENT '状态 0为禁用、1为启用',
+  `restored_from` int(10) unsigned DEFAULT '0' COMMENT '恢复自的版本号',
+  `created_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_article_revision` (`article_id`,`revision`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章版本';
\ No newline at end of file
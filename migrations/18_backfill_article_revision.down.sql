@@ -0,0 +1 @@
+DELETE FROM `blog_article_revision` WHERE `revision` = 1;
\ No newline at end of file
@@ -0,0 +1,8 @@
+INSERT IGNORE INTO `blog_article_revision`
+  (`article_id`, `revision`, `tag_ids`, `title`, `desc`, `content`, `cover_image_url`, `state`, `created_by`, `created_on`)
+  SELECT a.`id`, 1,
+    IFNULL((SELECT GROUP_CONCAT(t.`tag_id` ORDER BY t.`tag_id` = a.`tag_id` DESC, t.`tag_id`) FROM `blog_article_tag` t WHERE t.`article_id` = a.`id`), ''),
+    a.`title`, a.`desc`, a.`content`, a.`cover_image_url`, a.`state`,
+    IF(a.`modified_by` <> '', a.`modified_by`, a.`created_by`),
+    IF(a.`modified_on` > 0, a.`modified_on`, a.`created_on`)
+  FROM `blog_article` a;
\ No newline at end of file
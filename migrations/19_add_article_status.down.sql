@@ -0,0 +1,4 @@
+ALTER TABLE `blog_article`
+  DROP KEY `idx_status_publish_at`,
+  DROP COLUMN `publish_at`,
+  DROP COLUMN `status`;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'draft' COMMENT '发布状态 draft、in_review、scheduled、published、archived' AFTER `state`,
+  ADD COLUMN `publish_at` int(10) unsigned DEFAULT '0' COMMENT '发布时间' AFTER `status`,
+  ADD KEY `idx_status_publish_at` (`status`,`publish_at`);
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `blog_article` SET `status` = 'draft', `publish_at` = 0;
\ No newline at end of file
@@ -0,0 +1,3 @@
+UPDATE `blog_article`
+  SET `status` = IF(`state` = 1, 'published', 'draft'),
+    `publish_at` = IF(`state` = 1, `created_on`, 0);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  DROP KEY `uk_slug`,
+  DROP COLUMN `slug`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `slug` varchar(100) DEFAULT NULL COMMENT '链接别名' AFTER `title`,
+  ADD UNIQUE KEY `uk_slug` (`slug`);
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `blog_article` SET `slug` = NULL WHERE `slug` = CONCAT('article-', `id`);
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `blog_article` SET `slug` = CONCAT('article-', `id`) WHERE `slug` IS NULL;
\ No newline at end of file
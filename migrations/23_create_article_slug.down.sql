@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_article_slug`;
\ No newline at end of file
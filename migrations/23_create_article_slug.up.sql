@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS `blog_article_slug` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章ID',
+  `slug` varchar(100) NOT NULL COMMENT '旧链接别名',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '停用时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_slug` (`slug`),
+  KEY `idx_article_id` (`article_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章旧链接别名';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `blog_article`
+  DROP KEY `ft_title_desc_content`;
\ No newline at end of file
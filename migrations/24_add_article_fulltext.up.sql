@@ -0,0 +1,2 @@
+ALTER TABLE `blog_article`
+  ADD FULLTEXT KEY `ft_title_desc_content` (`title`,`desc`,`content`) WITH PARSER ngram;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `content_format` varchar(20) NOT NULL DEFAULT 'plain' COMMENT '内容格式 plain、markdown、html' AFTER `content`,
+  ADD COLUMN `content_html` mediumtext COMMENT '渲染后的内容' AFTER `content_format`;
\ No newline at end of file
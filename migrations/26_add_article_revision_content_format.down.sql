@@ -0,0 +1,2 @@
+ALTER TABLE `blog_article_revision`
+  DROP COLUMN `content_format`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `blog_article_revision`
+  ADD COLUMN `content_format` varchar(20) NOT NULL DEFAULT 'plain' COMMENT '内容格式 plain、markdown、html' AFTER `content`;
\ No newline at end of file
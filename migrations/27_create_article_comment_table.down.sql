@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_article_comment`;
\ No newline at end of file
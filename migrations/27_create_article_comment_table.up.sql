@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `blog_article_comment` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章ID',
+  `parent_id` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '回复的评论ID',
+  `root_id` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '所属顶层评论ID',
+  `auth_id` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '评论人ID',
+  `created_by` varchar(100) DEFAULT '' COMMENT '评论人',
+  `content` text COMMENT '内容',
+  `state` varchar(20) NOT NULL DEFAULT 'pending' COMMENT '状态 pending、approved、spam、deleted',
+  `moderated_by` varchar(100) DEFAULT '' COMMENT '审核人',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '评论时间',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_article_state` (`article_id`,`state`),
+  KEY `idx_root_id` (`root_id`),
+  KEY `idx_state` (`state`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='文章评论';
\ No newline at end of file
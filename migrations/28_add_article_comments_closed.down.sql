@@ -0,0 +1 @@
+ALTER TABLE `blog_article` DROP COLUMN `comments_closed`;
\ No newline at end of file
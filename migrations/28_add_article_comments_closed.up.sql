@@ -0,0 +1,2 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `comments_closed` tinyint(3) unsigned DEFAULT '0' COMMENT '是否关闭评论 0为开放、1为关闭' AFTER `publish_at`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  DROP KEY `idx_view_count`,
+  DROP COLUMN `view_count`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `view_count` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '浏览次数' AFTER `comments_closed`,
+  ADD KEY `idx_view_count` (`view_count`);
\ No newline at end of file
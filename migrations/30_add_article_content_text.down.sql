@@ -0,0 +1,4 @@
+ALTER TABLE `blog_article`
+  DROP KEY `ft_title_desc_content_text`,
+  DROP COLUMN `content_text`,
+  ADD FULLTEXT KEY `ft_title_desc_content` (`title`,`desc`,`content`) WITH PARSER ngram;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `content_text` mediumtext COMMENT '渲染后内容的纯文本，用于搜索' AFTER `content_html`,
+  DROP KEY `ft_title_desc_content`,
+  ADD FULLTEXT KEY `ft_title_desc_content_text` (`title`,`desc`,`content_text`) WITH PARSER ngram;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `blog_auth` DROP COLUMN `role`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `blog_auth` ADD COLUMN `role` varchar(20) NOT NULL DEFAULT 'reader' COMMENT '角色 admin、editor、author、reader';
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE `blog_auth`
+  DROP INDEX `uk_email`,
+  DROP INDEX `uk_username`,
+  DROP COLUMN `created_on`,
+  DROP COLUMN `status`,
+  DROP COLUMN `display_name`,
+  DROP COLUMN `email`;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE `blog_auth`
+  ADD COLUMN `email` varchar(100) DEFAULT NULL COMMENT '邮箱',
+  ADD COLUMN `display_name` varchar(100) DEFAULT '' COMMENT '昵称',
+  ADD COLUMN `status` tinyint(3) unsigned NOT NULL DEFAULT '1' COMMENT '状态 0为禁用、1为启用',
+  ADD COLUMN `created_on` int(10) unsigned DEFAULT '0' COMMENT '注册时间',
+  ADD UNIQUE KEY `uk_username` (`username`),
+  ADD UNIQUE KEY `uk_email` (`email`);
\ No newline at end of file
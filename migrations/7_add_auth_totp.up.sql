@@ -0,0 +1,4 @@
+ALTER TABLE `blog_auth`
+  ADD COLUMN `totp_secret` varchar(64) NOT NULL DEFAULT '' COMMENT 'TOTP密钥',
+  ADD COLUMN `totp_enabled` tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT '是否启用两步验证 0为否、1为是',
+  ADD COLUMN `totp_recovery_codes` text COMMENT '恢复码哈希';
\ No newline at end of file
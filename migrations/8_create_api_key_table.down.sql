@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_api_key`;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `blog_api_key` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `auth_id` int(10) unsigned NOT NULL COMMENT '所属用户ID',
+  `name` varchar(100) DEFAULT '' COMMENT '名称',
+  `prefix` varchar(16) NOT NULL COMMENT '密钥前缀',
+  `key_hash` char(64) NOT NULL COMMENT '密钥哈希',
+  `scopes` varchar(255) DEFAULT '' COMMENT '权限范围，为空时为用户的全部权限',
+  `expires_on` int(10) unsigned DEFAULT '0' COMMENT '过期时间，0为永不过期',
+  `last_used_on` int(10) unsigned DEFAULT '0' COMMENT '最后使用时间',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '吊销时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_prefix` (`prefix`),
+  KEY `idx_auth_id` (`auth_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='API密钥管理';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_oauth_client`;
\ No newline at end of file
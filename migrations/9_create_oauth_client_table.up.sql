@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `blog_oauth_client` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `client_id` varchar(64) NOT NULL COMMENT '客户端ID',
+  `secret_hash` char(64) NOT NULL COMMENT '客户端密钥哈希',
+  `name` varchar(100) DEFAULT '' COMMENT '名称',
+  `scopes` varchar(255) DEFAULT '' COMMENT '允许的权限范围',
+  `state` tinyint(3) unsigned DEFAULT '1' COMMENT '状态 0为禁用、1为启用',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_client_id` (`client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='OAuth2客户端管理';
\ No newline at end of file
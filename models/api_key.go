@@ -0,0 +1,90 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+type ApiKey struct {
+	Model
+
+	AuthID     int    `json:"auth_id"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix"`
+	KeyHash    string `json:"-"`
+	Scopes     string `json:"scopes"`
+	ExpiresOn  int    `json:"expires_on"`
+	LastUsedOn int    `json:"last_used_on"`
+}
+
+// GetApiKeyByPrefix gets a live API key by its prefix
+func GetApiKeyByPrefix(prefix string) (*ApiKey, error) {
+	var key ApiKey
+	err := db.Where("prefix = ? AND deleted_on = ?", prefix, 0).First(&key).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// GetApiKeys gets the live API keys of an account
+func GetApiKeys(authID int) ([]*ApiKey, error) {
+	var keys []*ApiKey
+	err := db.Where("auth_id = ? AND deleted_on = ?", authID, 0).Order("id desc").Find(&keys).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// ExistApiKeyByID checks if a live API key of an account exists based on ID
+func ExistApiKeyByID(id, authID int) (bool, error) {
+	var key ApiKey
+	err := db.Select("id").Where("id = ? AND auth_id = ? AND deleted_on = ?", id, authID, 0).First(&key).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return key.ID > 0, nil
+}
+
+// AddApiKey add an API key, the key must already be hashed
+func AddApiKey(data map[string]interface{}) error {
+	key := ApiKey{
+		AuthID:    data["auth_id"].(int),
+		Name:      data["name"].(string),
+		Prefix:    data["prefix"].(string),
+		KeyHash:   data["key_hash"].(string),
+		Scopes:    data["scopes"].(string),
+		ExpiresOn: data["expires_on"].(int),
+	}
+	if err := db.Create(&key).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TouchApiKey records when an API key was last used
+func TouchApiKey(id, usedOn int) error {
+	return db.Model(&ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_on", usedOn).Error
+}
+
+// DeleteApiKey revoke a single API key of an account
+func DeleteApiKey(id, authID int) error {
+	if err := db.Where("id = ? AND auth_id = ?", id, authID).Delete(ApiKey{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteApiKeysByAuth revoke every API key of an account
+func DeleteApiKeysByAuth(authID int) error {
+	if err := db.Where("auth_id = ? AND deleted_on = ?", authID, 0).Delete(ApiKey{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
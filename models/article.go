@@ -2,21 +2,60 @@ package models
 
 import (
 	"github.com/jinzhu/gorm"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/sanitize"
+)
+
+// Statuses of the publishing workflow, only published articles are shown to readers
+const (
+	ARTICLE_STATUS_DRAFT     = "draft"
+	ARTICLE_STATUS_IN_REVIEW = "in_review"
+	ARTICLE_STATUS_SCHEDULED = "scheduled"
+	ARTICLE_STATUS_PUBLISHED = "published"
+	ARTICLE_STATUS_ARCHIVED  = "archived"
+)
+
+// Formats of the content of an article, it is rendered to ContentHtml when written
+const (
+	ARTICLE_FORMAT_PLAIN    = "plain"
+	ARTICLE_FORMAT_MARKDOWN = "markdown"
+	ARTICLE_FORMAT_HTML     = "html"
 )
 
 type Article struct {
 	Model
 
-	TagID int `json:"tag_id" gorm:"index"`
-	Tag   Tag `json:"tag"`
+	// TagID and Tag are the first of Tags, kept for clients that predate multiple tags
+	TagID int   `json:"tag_id" gorm:"index"`
+	Tag   Tag   `json:"tag"`
+	Tags  []Tag `json:"tags" gorm:"many2many:article_tag;save_associations:false"`
 
 	Title         string `json:"title"`
+	Slug          string `json:"slug"`
 	Desc          string `json:"desc"`
 	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	ContentHtml   string `json:"content_html"`
+	// ContentText is the text of ContentHtml without the markup, both search engines index it
+	ContentText   string `json:"-"`
 	CoverImageUrl string `json:"cover_image_url"`
 	CreatedBy     string `json:"created_by"`
+	CreatedByID   int    `json:"created_by_id"`
 	ModifiedBy    string `json:"modified_by"`
-	State         int    `json:"state"`
+	// State is 1 for published articles and 0 otherwise, kept for clients that predate Status
+	State     int    `json:"state"`
+	Status    string `json:"status"`
+	PublishAt int    `json:"publish_at"`
+	// CommentsClosed is 1 when the owner stopped new comments
+	CommentsClosed int `json:"comments_closed"`
+	// ViewCount is the number of views, updated from the view counters every ViewFlushInterval
+	ViewCount int `json:"view_count"`
+	// CommentCount is the number of approved comments, counted when the article is read
+	CommentCount int `json:"comment_count" gorm:"-"`
+
+	// Toc and ReadingTime are derived from ContentHtml for single articles
+	Toc         []sanitize.Heading `json:"toc,omitempty" gorm:"-"`
+	ReadingTime int                `json:"reading_time,omitempty" gorm:"-"`
 }
 
 // ExistArticleByID checks if an article exists based on ID
@@ -34,10 +73,11 @@ func ExistArticleByID(id int) (bool, error) {
 	return false, nil
 }
 
-// GetArticleTotal gets the total number of articles based on the constraints
-func GetArticleTotal(maps interface{}) (int, error) {
+// GetArticleTotal gets the total number of articles based on the constraints,
+// scopes add conditions that cannot be expressed as equality maps
+func GetArticleTotal(maps interface{}, scopes ...func(*gorm.DB) *gorm.DB) (int, error) {
 	var count int
-	if err := db.Model(&Article{}).Where(maps).Count(&count).Error; err != nil {
+	if err := db.Model(&Article{}).Scopes(scopes...).Where(maps).Count(&count).Error; err != nil {
 		return 0, err
 	}
 
@@ -45,9 +85,9 @@ func GetArticleTotal(maps interface{}) (int, error) {
 }
 
 // GetArticles gets a list of articles based on paging constraints
-func GetArticles(pageNum int, pageSize int, maps interface{}) ([]*Article, error) {
+func GetArticles(pageNum int, pageSize int, maps interface{}, scopes ...func(*gorm.DB) *gorm.DB) ([]*Article, error) {
 	var articles []*Article
-	err := db.Preload("Tag").Where(maps).Offset(pageNum).Limit(pageSize).Find(&articles).Error
+	err := db.Preload("Tag").Preload("Tags", "deleted_on = ?", 0).Scopes(scopes...).Where(maps).Offset(pageNum).Limit(pageSize).Find(&articles).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, err
 	}
@@ -68,34 +108,136 @@ func GetArticle(id int) (*Article, error) {
 		return nil, err
 	}
 
+	err = db.Model(&article).Where("deleted_on = ?", 0).Related(&article.Tags, "Tags").Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
 	return &article, nil
 }
 
-// EditArticle modify a single article
-func EditArticle(id int, data interface{}) error {
-	if err := db.Model(&Article{}).Where("id = ? AND deleted_on = ? ", id, 0).Updates(data).Error; err != nil {
+// EditArticle modify a single article with its tags and record the result as a new revision,
+// data may carry restored_from when the edit restores an earlier revision. When data changes
+// the slug, the old one is kept as a former slug.
+func EditArticle(id int, data map[string]interface{}) error {
+	fields := make(map[string]interface{})
+	for k, v := range data {
+		if k != "tag_ids" && k != "restored_from" {
+			fields[k] = v
+		}
+	}
+	tagIDs := data["tag_ids"].([]int)
+	restoredFrom, _ := data["restored_from"].(int)
+
+	tx := db.Begin()
+	if slug, ok := data["slug"].(string); ok {
+		if err := moveArticleSlug(tx, id, slug); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Model(&Article{}).Where("id = ? AND deleted_on = ? ", id, 0).Updates(fields).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := replaceArticleTags(tx, id, tagIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := addArticleRevision(tx, id, tagIDs, data["modified_by"].(string), restoredFrom); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	return nil
+	return tx.Commit().Error
 }
 
-// AddArticle add a single article
-func AddArticle(data map[string]interface{}) error {
+// EditArticleStatus moves an article from one status to another, it reports false
+// if the article no longer has the from status, e.g. because another request moved it first
+func EditArticleStatus(id int, from string, data map[string]interface{}) (bool, error) {
+	query := db.Model(&Article{}).Where("id = ? AND status = ? AND deleted_on = ? ", id, from, 0).Updates(data)
+	if query.Error != nil {
+		return false, query.Error
+	}
+
+	return query.RowsAffected > 0, nil
+}
+
+// GetDueArticleIDs gets the IDs of scheduled articles whose publishing time has come
+func GetDueArticleIDs(now int) ([]int, error) {
+	var ids []int
+	err := db.Model(&Article{}).Where("status = ? AND publish_at <= ? AND deleted_on = ? ", ARTICLE_STATUS_SCHEDULED, now, 0).Pluck("id", &ids).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// PublishDueArticle publishes a scheduled article if its publishing time has come,
+// it reports false if it was published, rescheduled or unscheduled in the meantime
+func PublishDueArticle(id, now int) (bool, error) {
+	query := db.Model(&Article{}).Where("id = ? AND status = ? AND publish_at <= ? AND deleted_on = ? ", id, ARTICLE_STATUS_SCHEDULED, now, 0).
+		Updates(map[string]interface{}{"status": ARTICLE_STATUS_PUBLISHED, "state": 1})
+	if query.Error != nil {
+		return false, query.Error
+	}
+
+	return query.RowsAffected > 0, nil
+}
+
+// AddArticle add a single article with its tags and its first revision, returning its ID
+func AddArticle(data map[string]interface{}) (int, error) {
 	article := Article{
 		TagID:         data["tag_id"].(int),
 		Title:         data["title"].(string),
+		Slug:          data["slug"].(string),
 		Desc:          data["desc"].(string),
 		Content:       data["content"].(string),
+		ContentFormat: data["content_format"].(string),
+		ContentHtml:   data["content_html"].(string),
+		ContentText:   data["content_text"].(string),
 		CreatedBy:     data["created_by"].(string),
+		CreatedByID:   data["created_by_id"].(int),
 		State:         data["state"].(int),
+		Status:        data["status"].(string),
 		CoverImageUrl: data["cover_image_url"].(string),
 	}
-	if err := db.Create(&article).Error; err != nil {
-		return err
+
+	tx := db.Begin()
+	if err := tx.Create(&article).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := replaceArticleTags(tx, article.ID, data["tag_ids"].([]int)); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := addArticleRevision(tx, article.ID, data["tag_ids"].([]int), article.CreatedBy, 0); err != nil {
+		tx.Rollback()
+		return 0, err
 	}
 
-	return nil
+	if err := tx.Commit().Error; err != nil {
+		return 0, err
+	}
+
+	return article.ID, nil
+}
+
+// ArticleAuthorScope limits articles to those created by a user or shared with them with one of the given roles
+func ArticleAuthorScope(authID int, roles ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		shared := db.New().Model(&ArticleAuthor{}).Select("article_id").Where("auth_id = ? AND role IN (?)", authID, roles)
+		return db.Where("created_by_id = ? OR id IN (?)", authID, shared.QueryExpr())
+	}
+}
+
+// ArticleLatestScope orders articles by when they were published, latest first
+func ArticleLatestScope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order("publish_at desc, id desc")
+	}
 }
 
 // DeleteArticle delete a single article
@@ -107,11 +249,85 @@ func DeleteArticle(id int) error {
 	return nil
 }
 
-// CleanAllArticle clear all article
-func CleanAllArticle() error {
-	if err := db.Unscoped().Where("deleted_on != ? ", 0).Delete(&Article{}).Error; err != nil {
-		return err
+// GetDeletedArticles gets a page of the soft-deleted articles, latest deleted first
+func GetDeletedArticles(pageNum int, pageSize int) ([]*Article, error) {
+	var articles []*Article
+	err := db.Where("deleted_on != ?", 0).Order("deleted_on desc").Offset(pageNum).Limit(pageSize).Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
 	}
 
-	return nil
+	return articles, nil
+}
+
+// GetDeletedArticleTotal counts the soft-deleted articles
+func GetDeletedArticleTotal() (int, error) {
+	var count int
+	if err := db.Model(&Article{}).Where("deleted_on != ?", 0).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ExistDeletedArticleByID checks if a soft-deleted article exists based on ID
+func ExistDeletedArticleByID(id int) (bool, error) {
+	var article Article
+	err := db.Select("id").Where("id = ? AND deleted_on != ? ", id, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return article.ID > 0, nil
+}
+
+// RestoreArticle undoes the soft delete of an article
+func RestoreArticle(id int) error {
+	return db.Model(&Article{}).Where("id = ? AND deleted_on != ? ", id, 0).Updates(map[string]interface{}{"deleted_on": 0}).Error
+}
+
+// PurgeArticle permanently deletes a soft-deleted article with its tags, co-authors, revisions, former slugs and comments
+func PurgeArticle(id int) error {
+	_, err := purgeArticles("id = ? AND deleted_on != ? ", id, 0)
+	return err
+}
+
+// CleanAllArticle permanently deletes the articles soft-deleted before a unix time, returning their IDs
+func CleanAllArticle(before int) ([]int, error) {
+	return purgeArticles("deleted_on != ? AND deleted_on < ? ", 0, before)
+}
+
+// purgeArticles permanently deletes the articles matching the conditions and the rows that belong to them,
+// returning the IDs of the deleted articles
+func purgeArticles(query string, args ...interface{}) ([]int, error) {
+	tx := db.Begin()
+
+	// Lock the articles so that none is restored while its rows are deleted
+	var articles []Article
+	err := tx.Set("gorm:query_option", "FOR UPDATE").Select("id").Where(query, args...).Find(&articles).Error
+	if err != nil || len(articles) == 0 {
+		tx.Rollback()
+		return nil, err
+	}
+	ids := make([]int, 0, len(articles))
+	for _, v := range articles {
+		ids = append(ids, v.ID)
+	}
+
+	for _, value := range []interface{}{ArticleTag{}, ArticleAuthor{}, ArticleRevision{}, ArticleSlug{}, ArticleComment{}} {
+		if err := tx.Where("article_id IN (?)", ids).Delete(value).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Unscoped().Where("id IN (?)", ids).Delete(Article{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
 }
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// ArticleAuthor grants a user a role on an article created by someone else
+type ArticleAuthor struct {
+	ID         int    `gorm:"primary_key" json:"id"`
+	ArticleID  int    `json:"article_id"`
+	AuthID     int    `json:"auth_id"`
+	Role       string `json:"role"`
+	CreatedBy  string `json:"created_by"`
+	CreatedOn  int    `json:"created_on"`
+	ModifiedOn int    `json:"modified_on"`
+}
+
+// GetArticleAuthors gets the co-authors of an article
+func GetArticleAuthors(articleID int) ([]*ArticleAuthor, error) {
+	var authors []*ArticleAuthor
+	err := db.Where("article_id = ?", articleID).Order("id").Find(&authors).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// GetArticleAuthor gets the co-author entry of a user on an article, with ID 0 if there is none
+func GetArticleAuthor(articleID, authID int) (*ArticleAuthor, error) {
+	var author ArticleAuthor
+	err := db.Where("article_id = ? AND auth_id = ?", articleID, authID).First(&author).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+// AddArticleAuthor add a co-author to an article
+func AddArticleAuthor(data map[string]interface{}) error {
+	author := ArticleAuthor{
+		ArticleID: data["article_id"].(int),
+		AuthID:    data["auth_id"].(int),
+		Role:      data["role"].(string),
+		CreatedBy: data["created_by"].(string),
+	}
+	if err := db.Create(&author).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EditArticleAuthor change the role of a co-author
+func EditArticleAuthor(articleID, authID int, role string) error {
+	return db.Model(&ArticleAuthor{}).Where("article_id = ? AND auth_id = ?", articleID, authID).
+		Updates(map[string]interface{}{"role": role}).Error
+}
+
+// DeleteArticleAuthor remove a co-author from an article
+func DeleteArticleAuthor(articleID, authID int) error {
+	return db.Where("article_id = ? AND auth_id = ?", articleID, authID).Delete(ArticleAuthor{}).Error
+}
+
+// DeleteArticleAuthorsByAuth remove a user from every article they were added to
+func DeleteArticleAuthorsByAuth(authID int) error {
+	return db.Where("auth_id = ?", authID).Delete(ArticleAuthor{}).Error
+}
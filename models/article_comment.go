@@ -0,0 +1,169 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// States of a comment, only approved comments are shown to readers
+const (
+	COMMENT_STATE_PENDING  = "pending"
+	COMMENT_STATE_APPROVED = "approved"
+	COMMENT_STATE_SPAM     = "spam"
+	COMMENT_STATE_DELETED  = "deleted"
+)
+
+// ArticleComment is a comment on an article or a reply to another comment
+type ArticleComment struct {
+	ID        int `gorm:"primary_key" json:"id"`
+	ArticleID int `json:"article_id"`
+	// ParentID is the comment replied to and RootID the top-level comment of the thread, both 0 for top-level comments
+	ParentID    int    `json:"parent_id"`
+	RootID      int    `json:"root_id"`
+	AuthID      int    `json:"auth_id"`
+	CreatedBy   string `json:"created_by"`
+	Content     string `json:"content"`
+	State       string `json:"state"`
+	ModeratedBy string `json:"moderated_by"`
+	CreatedOn   int    `json:"created_on"`
+	ModifiedOn  int    `json:"modified_on"`
+}
+
+// AddArticleComment add a single comment, returning its ID
+func AddArticleComment(data map[string]interface{}) (int, error) {
+	comment := ArticleComment{
+		ArticleID: data["article_id"].(int),
+		ParentID:  data["parent_id"].(int),
+		RootID:    data["root_id"].(int),
+		AuthID:    data["auth_id"].(int),
+		CreatedBy: data["created_by"].(string),
+		Content:   data["content"].(string),
+		State:     data["state"].(string),
+	}
+	if err := db.Create(&comment).Error; err != nil {
+		return 0, err
+	}
+
+	return comment.ID, nil
+}
+
+// GetArticleComment gets a single comment, with ID 0 if there is none
+func GetArticleComment(id int) (*ArticleComment, error) {
+	var comment ArticleComment
+	err := db.Where("id = ?", id).First(&comment).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetArticleComments gets a page of the comments matching the constraints, latest first
+func GetArticleComments(pageNum int, pageSize int, maps interface{}) ([]*ArticleComment, error) {
+	var comments []*ArticleComment
+	err := db.Where(maps).Order("id desc").Offset(pageNum).Limit(pageSize).Find(&comments).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetArticleCommentTotal counts the comments matching the constraints
+func GetArticleCommentTotal(maps interface{}) (int, error) {
+	var count int
+	if err := db.Model(&ArticleComment{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// visibleThreads limits comments to the top-level comments of an article that are approved,
+// or deleted but with approved replies which keep the thread visible
+func visibleThreads(articleID int) *gorm.DB {
+	approvedRoots := db.New().Model(&ArticleComment{}).Select("root_id").Where("article_id = ? AND state = ?", articleID, COMMENT_STATE_APPROVED)
+	return db.Model(&ArticleComment{}).Where("article_id = ? AND parent_id = ?", articleID, 0).
+		Where("state = ? OR (state = ? AND id IN (?))", COMMENT_STATE_APPROVED, COMMENT_STATE_DELETED, approvedRoots.QueryExpr())
+}
+
+// GetArticleCommentThreads gets a page of the visible top-level comments of an article, oldest first
+func GetArticleCommentThreads(articleID, pageNum, pageSize int) ([]*ArticleComment, error) {
+	var comments []*ArticleComment
+	err := visibleThreads(articleID).Order("id").Offset(pageNum).Limit(pageSize).Find(&comments).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetArticleCommentThreadTotal counts the visible top-level comments of an article
+func GetArticleCommentThreadTotal(articleID int) (int, error) {
+	var count int
+	if err := visibleThreads(articleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetArticleCommentReplies gets the approved and deleted replies in the threads of the top-level comments, oldest first
+func GetArticleCommentReplies(rootIDs []int) ([]*ArticleComment, error) {
+	if len(rootIDs) == 0 {
+		return nil, nil
+	}
+
+	var comments []*ArticleComment
+	err := db.Where("root_id IN (?) AND state IN (?)", rootIDs, []string{COMMENT_STATE_APPROVED, COMMENT_STATE_DELETED}).
+		Order("id").Find(&comments).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// EditArticleCommentsState moves comments to a state, returning the number of comments changed
+func EditArticleCommentsState(ids []int, state, moderatedBy string) (int, error) {
+	query := db.Model(&ArticleComment{}).Where("id IN (?) AND state != ?", ids, state).
+		Updates(map[string]interface{}{"state": state, "moderated_by": moderatedBy})
+	if query.Error != nil {
+		return 0, query.Error
+	}
+
+	return int(query.RowsAffected), nil
+}
+
+// GetArticleCommentCounts counts the approved comments of each of the articles, articles without any are left out
+func GetArticleCommentCounts(articleIDs []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ArticleID int
+		Count     int
+	}
+	err := db.Model(&ArticleComment{}).Select("article_id, COUNT(*) AS count").
+		Where("article_id IN (?) AND state = ?", articleIDs, COMMENT_STATE_APPROVED).Group("article_id").Scan(&rows).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Count
+	}
+
+	return counts, nil
+}
+
+// EditArticleCommentsClosed closes the comments of an article, or opens them again. The article
+// keeps its modified_on, its content did not change.
+func EditArticleCommentsClosed(articleID int, closed bool) error {
+	value := 0
+	if closed {
+		value = 1
+	}
+
+	return db.Model(&Article{}).Where("id = ? AND deleted_on = ? ", articleID, 0).UpdateColumn("comments_closed", value).Error
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ArticleRevision is a snapshot of an article after it was added, edited or restored
+type ArticleRevision struct {
+	ID            int    `gorm:"primary_key" json:"id"`
+	ArticleID     int    `json:"article_id"`
+	Revision      int    `json:"revision"`
+	TagIDs        string `gorm:"column:tag_ids" json:"tag_ids"`
+	Title         string `json:"title"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	CoverImageUrl string `json:"cover_image_url"`
+	State         int    `json:"state"`
+	RestoredFrom  int    `json:"restored_from"`
+	CreatedBy     string `json:"created_by"`
+	CreatedOn     int    `json:"created_on"`
+}
+
+// GetArticleRevisions gets the revisions of an article, latest first
+func GetArticleRevisions(articleID, pageNum, pageSize int) ([]*ArticleRevision, error) {
+	var revisions []*ArticleRevision
+	err := db.Where("article_id = ?", articleID).Order("revision desc").Offset(pageNum).Limit(pageSize).Find(&revisions).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetArticleRevisionTotal counts the revisions of an article
+func GetArticleRevisionTotal(articleID int) (int, error) {
+	var count int
+	if err := db.Model(&ArticleRevision{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetArticleRevision gets a revision of an article by its number, with ID 0 if there is none
+func GetArticleRevision(articleID, revision int) (*ArticleRevision, error) {
+	var rev ArticleRevision
+	err := db.Where("article_id = ? AND revision = ?", articleID, revision).First(&rev).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+// addArticleRevision snapshots the article as written in tx under the next revision number,
+// the article row is locked by the write so concurrent edits cannot pick the same number
+func addArticleRevision(tx *gorm.DB, articleID int, tagIDs []int, createdBy string, restoredFrom int) error {
+	var article Article
+	if err := tx.Where("id = ?", articleID).First(&article).Error; err != nil {
+		return err
+	}
+
+	var latest int
+	row := tx.Model(&ArticleRevision{}).Select("IFNULL(MAX(revision), 0)").Where("article_id = ?", articleID).Row()
+	if err := row.Scan(&latest); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	return tx.Create(&ArticleRevision{
+		ArticleID:     articleID,
+		Revision:      latest + 1,
+		TagIDs:        strings.Join(ids, ","),
+		Title:         article.Title,
+		Desc:          article.Desc,
+		Content:       article.Content,
+		ContentFormat: article.ContentFormat,
+		CoverImageUrl: article.CoverImageUrl,
+		State:         article.State,
+		RestoredFrom:  restoredFrom,
+		CreatedBy:     createdBy,
+	}).Error
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/search"
+)
+
+// FULLTEXT_MIN_TERM is the ngram_token_size of MySQL, shorter terms are not in the FULLTEXT index
+const FULLTEXT_MIN_TERM = 2
+
+// SearchArticles finds up to limit articles containing every term with the FULLTEXT index, best match first.
+// Terms shorter than FULLTEXT_MIN_TERM are ignored, articles in the trash are included.
+func SearchArticles(terms []string, limit int) ([]search.Hit, error) {
+	var phrases []string
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) >= FULLTEXT_MIN_TERM {
+			// Terms only hold letters and digits, nothing to escape
+			phrases = append(phrases, `+"`+term+`"`)
+		}
+	}
+	if len(phrases) == 0 {
+		return nil, nil
+	}
+
+	match := "MATCH(`title`, `desc`, `content_text`) AGAINST(? IN BOOLEAN MODE)"
+	query := strings.Join(phrases, " ")
+	var hits []search.Hit
+	err := db.Model(&Article{}).Select("id, "+match+" AS score", query).Where(match, query).
+		Order("score desc, id desc").Limit(limit).Scan(&hits).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// GetMatchingArticleIDs keeps the IDs of the articles that match the constraints
+func GetMatchingArticleIDs(ids []int, maps interface{}, scopes ...func(*gorm.DB) *gorm.DB) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var matching []int
+	err := db.Model(&Article{}).Scopes(scopes...).Where(maps).Where("id IN (?)", ids).Pluck("id", &matching).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return matching, nil
+}
+
+// GetArticlesByIDs gets the articles with the IDs, in no particular order
+func GetArticlesByIDs(ids []int) ([]*Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var articles []*Article
+	err := db.Preload("Tag").Preload("Tags", "deleted_on = ?", 0).Where("id IN (?)", ids).Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// GetArticleDocuments gets the searchable text of up to limit articles with an ID above afterID,
+// in ID order, articles in the trash included. The content is the plain text of the rendered content.
+func GetArticleDocuments(afterID, limit int) ([]search.Document, error) {
+	var docs []search.Document
+	err := db.Model(&Article{}).Select("id, title, `desc`, COALESCE(content_text, '') AS content").Where("id > ?", afterID).
+		Order("id").Limit(limit).Scan(&docs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// GetArticlesWithoutContentText gets up to limit articles with an ID above afterID and content but no
+// content_text, in ID order, articles in the trash included. They were written before content_text existed.
+func GetArticlesWithoutContentText(afterID, limit int) ([]*Article, error) {
+	var articles []*Article
+	err := db.Select("id, content, content_html").Where("id > ? AND (content_text IS NULL OR content_text = '') AND content != ''", afterID).
+		Order("id").Limit(limit).Find(&articles).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// EditArticleContentText sets the content_text of an article, leaving modified_on alone
+func EditArticleContentText(id int, contentText string) error {
+	return db.Model(&Article{}).Where("id = ?", id).UpdateColumn("content_text", contentText).Error
+}
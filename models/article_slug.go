@@ -0,0 +1,87 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// ArticleSlug is a former slug of an article, kept so that links using it keep working
+type ArticleSlug struct {
+	ID        int    `gorm:"primary_key" json:"id"`
+	ArticleID int    `json:"article_id"`
+	Slug      string `json:"slug"`
+	CreatedOn int    `json:"created_on"`
+}
+
+// ExistArticleSlug checks if an article other than exceptID uses a slug, now or formerly.
+// Articles in the trash keep their slugs.
+func ExistArticleSlug(slug string, exceptID int) (bool, error) {
+	var article Article
+	err := db.Select("id").Where("slug = ? AND id != ?", slug, exceptID).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+	if article.ID > 0 {
+		return true, nil
+	}
+
+	var former ArticleSlug
+	err = db.Select("id").Where("slug = ? AND article_id != ?", slug, exceptID).First(&former).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return former.ID > 0, nil
+}
+
+// GetArticleIDBySlug gets the ID of the live article with a slug, 0 if there is none
+func GetArticleIDBySlug(slug string) (int, error) {
+	var article Article
+	err := db.Select("id").Where("slug = ? AND deleted_on = ? ", slug, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+
+	return article.ID, nil
+}
+
+// GetCurrentArticleSlug gets the slug of the live article that formerly had a slug, "" if there is none
+func GetCurrentArticleSlug(former string) (string, error) {
+	var articleSlug ArticleSlug
+	err := db.Where("slug = ?", former).First(&articleSlug).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", err
+	}
+	if articleSlug.ID == 0 {
+		return "", nil
+	}
+
+	var article Article
+	err = db.Select("slug").Where("id = ? AND deleted_on = ? ", articleSlug.ArticleID, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", err
+	}
+
+	return article.Slug, nil
+}
+
+// moveArticleSlug keeps the current slug of an article as a former one when it changes to slug
+func moveArticleSlug(tx *gorm.DB, articleID int, slug string) error {
+	var article Article
+	err := tx.Set("gorm:query_option", "FOR UPDATE").Select("slug").Where("id = ?", articleID).First(&article).Error
+	if err != nil {
+		return err
+	}
+	if article.Slug == slug {
+		return nil
+	}
+
+	// The article may take back one of its former slugs
+	if err := tx.Where("article_id = ? AND slug = ?", articleID, slug).Delete(ArticleSlug{}).Error; err != nil {
+		return err
+	}
+	if article.Slug == "" {
+		return nil
+	}
+
+	return tx.Create(&ArticleSlug{ArticleID: articleID, Slug: article.Slug}).Error
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// ArticleTag links an article to one of its tags
+type ArticleTag struct {
+	ArticleID int `gorm:"primary_key" json:"article_id"`
+	TagID     int `gorm:"primary_key" json:"tag_id"`
+}
+
+// replaceArticleTags sets the tags of an article
+func replaceArticleTags(tx *gorm.DB, articleID int, tagIDs []int) error {
+	if err := tx.Where("article_id = ?", articleID).Delete(ArticleTag{}).Error; err != nil {
+		return err
+	}
+
+	for _, tagID := range tagIDs {
+		if err := tx.Create(&ArticleTag{ArticleID: articleID, TagID: tagID}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ArticleTagScope limits articles to those carrying any, or if all is set every one, of the tags
+func ArticleTagScope(tagIDs []int, all bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tagged := db.New().Model(&ArticleTag{}).Select("article_id").Where("tag_id IN (?)", tagIDs)
+		if all {
+			tagged = tagged.Group("article_id").Having("COUNT(*) = ?", len(tagIDs))
+		}
+
+		return db.Where("id IN (?)", tagged.QueryExpr())
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// ArticleViews is the number of views of an article
+type ArticleViews struct {
+	ArticleID int `json:"article_id"`
+	Views     int `json:"views"`
+}
+
+// AddArticleViews adds views to the view counts of articles, articles in the trash included.
+// It does not change modified_on.
+func AddArticleViews(views map[int]int) error {
+	tx := db.Begin()
+	for id, n := range views {
+		err := tx.Model(&Article{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// GetMostViewedArticles gets up to limit live articles that have been viewed, most viewed first,
+// all of them if limit is 0
+func GetMostViewedArticles(limit int) ([]ArticleViews, error) {
+	query := db.Model(&Article{}).Select("id AS article_id, view_count AS views").
+		Where("view_count > ? AND deleted_on = ?", 0, 0).Order("view_count desc, id desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var views []ArticleViews
+	err := query.Scan(&views).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return views, nil
+}
@@ -1,25 +1,78 @@
 package models
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"errors"
+
 	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 )
 
+const (
+	AUTH_STATUS_DISABLED = 0
+	AUTH_STATUS_ACTIVE   = 1
+)
+
+// ErrAuthDisabled is returned by CheckAuth when the credentials are correct but the account is disabled
+var ErrAuthDisabled = errors.New("account is disabled")
+
 type Auth struct {
-	ID       int    `gorm:"primary_key" json:"id"`
-	Username string `gorm:"size:50" json:"username"`
-	Password string `gorm:"size:60" json:"password"`
+	ID          int    `gorm:"primary_key" json:"id"`
+	Username    string `gorm:"size:50" json:"username"`
+	Password    string `gorm:"size:60" json:"-"`
+	Role        string `gorm:"size:20" json:"role"`
+	Email       string `gorm:"size:100" json:"email"`
+	DisplayName string `gorm:"size:100" json:"display_name"`
+	Status      int    `json:"status"`
+	CreatedOn   int    `json:"created_on"`
+
+	TOTPSecret        string `gorm:"column:totp_secret;size:64" json:"-"`
+	TOTPEnabled       int    `gorm:"column:totp_enabled" json:"totp_enabled"`
+	TOTPRecoveryCodes string `gorm:"column:totp_recovery_codes" json:"-"`
 }
 
-// HashPassword hashes a plain text password
+// HashPassword hashes a plain text password with the configured bcrypt cost
 func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), getBcryptCost())
 	if err != nil {
 		return "", err
 	}
 	return string(hashedPassword), nil
 }
 
+// CheckPasswordHash compares a plain text password with a hash
+func CheckPasswordHash(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func getBcryptCost() int {
+	cost := setting.AppSetting.BcryptCost
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+
+	return cost
+}
+
+// rehashPassword upgrades the stored hash of an account whose password was just verified,
+// if it was made with a lower cost than configured. A failure only means it is tried again next time.
+func rehashPassword(id int, hash, password string) {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil || cost >= getBcryptCost() {
+		return
+	}
+
+	newHash, err := HashPassword(password)
+	if err == nil {
+		err = db.Model(&Auth{}).Where("id = ? AND password = ?", id, hash).UpdateColumn("password", newHash).Error
+	}
+	if err != nil {
+		logging.Warn("password rehash failed:", err)
+	}
+}
+
 // CheckAuth checks if authentication information exists and password is correct
 func CheckAuth(username, password string) (bool, error) {
 	var auth Auth
@@ -32,10 +85,169 @@ func CheckAuth(username, password string) (bool, error) {
 	}
 
 	// Compare the provided password with the hashed password
-	err = bcrypt.CompareHashAndPassword([]byte(auth.Password), []byte(password))
+	if !CheckPasswordHash(auth.Password, password) {
+		return false, nil
+	}
+
+	if auth.Status != AUTH_STATUS_ACTIVE {
+		return false, ErrAuthDisabled
+	}
+
+	rehashPassword(auth.ID, auth.Password, password)
+
+	return true, nil
+}
+
+// CheckAuthPassword checks the password of an account by ID
+func CheckAuthPassword(id int, password string) (bool, error) {
+	var auth Auth
+	err := db.Select("id, password").Where("id = ?", id).First(&auth).Error
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !CheckPasswordHash(auth.Password, password) {
 		return false, nil
 	}
 
+	rehashPassword(auth.ID, auth.Password, password)
+
 	return true, nil
 }
+
+// GetAuthByUsername gets an account by its username
+func GetAuthByUsername(username string) (*Auth, error) {
+	var auth Auth
+	err := db.Where("username = ?", username).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &auth, nil
+}
+
+// GetAuthByEmail gets an account by its email address
+func GetAuthByEmail(email string) (*Auth, error) {
+	var auth Auth
+	err := db.Where("email = ?", email).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &auth, nil
+}
+
+// GetAuth gets an account by ID
+func GetAuth(id int) (*Auth, error) {
+	var auth Auth
+	err := db.Where("id = ?", id).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &auth, nil
+}
+
+// ExistAuthByID checks if an account exists based on ID
+func ExistAuthByID(id int) (bool, error) {
+	var auth Auth
+	err := db.Select("id").Where("id = ?", id).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return auth.ID > 0, nil
+}
+
+// ExistAuthByUsername checks if an account with the username exists
+func ExistAuthByUsername(username string) (bool, error) {
+	var auth Auth
+	err := db.Select("id").Where("username = ?", username).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return auth.ID > 0, nil
+}
+
+// ExistAuthByEmail checks if another account uses the email, excludeID is ignored in the check
+func ExistAuthByEmail(email string, excludeID int) (bool, error) {
+	var auth Auth
+	err := db.Select("id").Where("email = ? AND id != ?", email, excludeID).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return auth.ID > 0, nil
+}
+
+// GetAuthTotal counts the total number of accounts based on the constraint
+func GetAuthTotal(maps interface{}) (int, error) {
+	var count int
+	if err := db.Model(&Auth{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetAuths gets a list of accounts based on paging and constraints
+func GetAuths(pageNum int, pageSize int, maps interface{}) ([]*Auth, error) {
+	var auths []*Auth
+	err := db.Where(maps).Offset(pageNum).Limit(pageSize).Find(&auths).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return auths, nil
+}
+
+// AddAuth add an account, the password must already be hashed
+func AddAuth(data map[string]interface{}) error {
+	auth := Auth{
+		Username:    data["username"].(string),
+		Password:    data["password"].(string),
+		Role:        data["role"].(string),
+		Email:       data["email"].(string),
+		DisplayName: data["display_name"].(string),
+		Status:      data["status"].(int),
+	}
+	if err := db.Create(&auth).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EditAuth modify a single account
+func EditAuth(id int, data interface{}) error {
+	if err := db.Model(&Auth{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceAuthRecoveryCodes swaps the recovery codes of an account only if they still equal old,
+// reporting whether they did, so that a recovery code cannot be redeemed twice concurrently
+func ReplaceAuthRecoveryCodes(id int, old, new string) (bool, error) {
+	query := db.Model(&Auth{}).Where("id = ? AND totp_recovery_codes = ?", id, old).
+		Update("totp_recovery_codes", new)
+	if err := query.Error; err != nil {
+		return false, err
+	}
+
+	return query.RowsAffected == 1, nil
+}
+
+// DeleteAuth delete a single account
+func DeleteAuth(id int) error {
+	if err := db.Where("id = ?", id).Delete(Auth{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
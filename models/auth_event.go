@@ -0,0 +1,70 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// AuthEvent is an entry of the authentication audit trail
+type AuthEvent struct {
+	ID        int    `gorm:"primary_key" json:"id"`
+	Username  string `json:"username"`
+	Event     string `json:"event"`
+	IP        string `gorm:"column:ip" json:"ip"`
+	UserAgent string `json:"user_agent"`
+	Detail    string `json:"detail"`
+	CreatedOn int    `json:"created_on"`
+}
+
+// AddAuthEvent add an audit trail entry
+func AddAuthEvent(data map[string]interface{}) error {
+	event := AuthEvent{
+		Username:  data["username"].(string),
+		Event:     data["event"].(string),
+		IP:        data["ip"].(string),
+		UserAgent: data["user_agent"].(string),
+		Detail:    data["detail"].(string),
+	}
+	if err := db.Create(&event).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthEvents gets audit trail entries, newest first, based on paging, constraints and
+// a created_on range where a zero bound is open
+func GetAuthEvents(pageNum int, pageSize int, maps interface{}, from, to int) ([]*AuthEvent, error) {
+	var events []*AuthEvent
+	query := authEventRange(db.Where(maps), from, to).Order("id desc")
+	if pageSize > 0 {
+		query = query.Offset(pageNum).Limit(pageSize)
+	}
+
+	err := query.Find(&events).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetAuthEventTotal counts the audit trail entries matching the constraints and range
+func GetAuthEventTotal(maps interface{}, from, to int) (int, error) {
+	var count int
+	if err := authEventRange(db.Model(&AuthEvent{}).Where(maps), from, to).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func authEventRange(query *gorm.DB, from, to int) *gorm.DB {
+	if from > 0 {
+		query = query.Where("created_on >= ?", from)
+	}
+	if to > 0 {
+		query = query.Where("created_on <= ?", to)
+	}
+
+	return query
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// AuthPasswordHistory is a password hash an account has used, kept to prevent reuse
+type AuthPasswordHistory struct {
+	ID        int    `gorm:"primary_key" json:"id"`
+	AuthID    int    `json:"auth_id"`
+	Password  string `gorm:"size:60" json:"-"`
+	CreatedOn int    `json:"created_on"`
+}
+
+// GetAuthPasswordHistory gets the latest password hashes of an account, newest first
+func GetAuthPasswordHistory(authID, limit int) ([]string, error) {
+	var history []*AuthPasswordHistory
+	err := db.Select("password").Where("auth_id = ?", authID).Order("id desc").Limit(limit).Find(&history).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(history))
+	for _, h := range history {
+		hashes = append(hashes, h.Password)
+	}
+
+	return hashes, nil
+}
+
+// AddAuthPasswordHistory records a password hash of an account and drops all but the latest keep entries
+func AddAuthPasswordHistory(authID int, password string, keep int) error {
+	if err := db.Create(&AuthPasswordHistory{AuthID: authID, Password: password}).Error; err != nil {
+		return err
+	}
+
+	var ids []int
+	err := db.Model(&AuthPasswordHistory{}).Where("auth_id = ?", authID).Order("id desc").Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) <= keep {
+		return nil
+	}
+
+	return db.Where("id IN (?)", ids[keep:]).Delete(AuthPasswordHistory{}).Error
+}
+
+// DeleteAuthPasswordHistory delete the password history of an account
+func DeleteAuthPasswordHistory(authID int) error {
+	return db.Where("auth_id = ?", authID).Delete(AuthPasswordHistory{}).Error
+}
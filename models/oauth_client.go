@@ -0,0 +1,86 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+type OauthClient struct {
+	Model
+
+	ClientID   string `json:"client_id"`
+	SecretHash string `json:"-"`
+	Name       string `json:"name"`
+	Scopes     string `json:"scopes"`
+	State      int    `json:"state"`
+	CreatedBy  string `json:"created_by"`
+}
+
+// GetOauthClientByClientID gets a live OAuth2 client by its client_id
+func GetOauthClientByClientID(clientID string) (*OauthClient, error) {
+	var client OauthClient
+	err := db.Where("client_id = ? AND deleted_on = ?", clientID, 0).First(&client).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// GetOauthClient gets a live OAuth2 client by ID
+func GetOauthClient(id int) (*OauthClient, error) {
+	var client OauthClient
+	err := db.Where("id = ? AND deleted_on = ?", id, 0).First(&client).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// GetOauthClients gets all live OAuth2 clients
+func GetOauthClients() ([]*OauthClient, error) {
+	var clients []*OauthClient
+	err := db.Where("deleted_on = ?", 0).Find(&clients).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// ExistOauthClientByID checks if a live OAuth2 client exists based on ID
+func ExistOauthClientByID(id int) (bool, error) {
+	var client OauthClient
+	err := db.Select("id").Where("id = ? AND deleted_on = ?", id, 0).First(&client).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return client.ID > 0, nil
+}
+
+// AddOauthClient add an OAuth2 client, the secret must already be hashed
+func AddOauthClient(data map[string]interface{}) error {
+	client := OauthClient{
+		ClientID:   data["client_id"].(string),
+		SecretHash: data["secret_hash"].(string),
+		Name:       data["name"].(string),
+		Scopes:     data["scopes"].(string),
+		State:      data["state"].(int),
+		CreatedBy:  data["created_by"].(string),
+	}
+	if err := db.Create(&client).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteOauthClient delete a single OAuth2 client
+func DeleteOauthClient(id int) error {
+	if err := db.Where("id = ?", id).Delete(OauthClient{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -86,6 +86,17 @@ func ExistTagByID(id int) (bool, error) {
 	return false, nil
 }
 
+// ExistTagsByIDs checks that every one of the IDs is a live tag
+func ExistTagsByIDs(ids []int) (bool, error) {
+	var count int
+	err := db.Model(&Tag{}).Where("id IN (?) AND deleted_on = ?", ids, 0).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count == len(ids), nil
+}
+
 // DeleteTag delete a tag
 func DeleteTag(id int) error {
 	if err := db.Where("id = ?", id).Delete(&Tag{}).Error; err != nil {
@@ -104,11 +115,110 @@ func EditTag(id int, data interface{}) error {
 	return nil
 }
 
-// CleanAllTag clear all tag
-func CleanAllTag() (bool, error) {
-	if err := db.Unscoped().Where("deleted_on != ? ", 0).Delete(&Tag{}).Error; err != nil {
-		return false, err
+// GetDeletedTags gets a page of the soft-deleted tags, latest deleted first
+func GetDeletedTags(pageNum int, pageSize int) ([]Tag, error) {
+	var tags []Tag
+	err := db.Where("deleted_on != ?", 0).Order("deleted_on desc").Offset(pageNum).Limit(pageSize).Find(&tags).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetDeletedTagTotal counts the soft-deleted tags
+func GetDeletedTagTotal() (int, error) {
+	var count int
+	if err := db.Model(&Tag{}).Where("deleted_on != ?", 0).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetTag gets a single tag, with ID 0 if there is none
+func GetTag(id int) (*Tag, error) {
+	var tag Tag
+	err := db.Where("id = ? AND deleted_on = ? ", id, 0).First(&tag).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// GetDeletedTag gets a soft-deleted tag, with ID 0 if there is none
+func GetDeletedTag(id int) (*Tag, error) {
+	var tag Tag
+	err := db.Where("id = ? AND deleted_on != ? ", id, 0).First(&tag).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// RestoreTag undoes the soft delete of a tag
+func RestoreTag(id int) error {
+	return db.Model(&Tag{}).Where("id = ? AND deleted_on != ? ", id, 0).Updates(map[string]interface{}{"deleted_on": 0}).Error
+}
+
+// PurgeTag permanently deletes a soft-deleted tag and removes it from its articles,
+// returning the IDs of the articles it was removed from
+func PurgeTag(id int) ([]int, error) {
+	_, articleIDs, err := purgeTags("id = ? AND deleted_on != ? ", id, 0)
+	return articleIDs, err
+}
+
+// CleanAllTag permanently deletes the tags soft-deleted before a unix time, returning their IDs
+// and the IDs of the articles they were removed from
+func CleanAllTag(before int) ([]int, []int, error) {
+	return purgeTags("deleted_on != ? AND deleted_on < ? ", 0, before)
+}
+
+// purgeTags permanently deletes the tags matching the conditions and their links to articles,
+// returning the IDs of the deleted tags and of the articles that carried them
+func purgeTags(query string, args ...interface{}) ([]int, []int, error) {
+	tx := db.Begin()
+
+	// Lock the tags so that none is restored while its links are deleted
+	var tags []Tag
+	err := tx.Set("gorm:query_option", "FOR UPDATE").Select("id").Where(query, args...).Find(&tags).Error
+	if err != nil || len(tags) == 0 {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	ids := make([]int, 0, len(tags))
+	for _, v := range tags {
+		ids = append(ids, v.ID)
+	}
+
+	var linkedIDs, primaryIDs []int
+	if err := tx.Model(&ArticleTag{}).Where("tag_id IN (?)", ids).Pluck("article_id", &linkedIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Model(&Article{}).Where("tag_id IN (?)", ids).Pluck("id", &primaryIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Where("tag_id IN (?)", ids).Delete(ArticleTag{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Model(&Article{}).Where("tag_id IN (?)", ids).UpdateColumn("tag_id", 0).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	if err := tx.Unscoped().Where("id IN (?)", ids).Delete(Tag{}).Error; err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, nil, err
 	}
 
-	return true, nil
+	return ids, append(linkedIDs, primaryIDs...), nil
 }
@@ -0,0 +1,176 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	EQUAL  = ' '
+	DELETE = '-'
+	INSERT = '+'
+)
+
+// maxCells bounds the LCS table, larger changes are reported as a full replacement
+const maxCells = 1 << 22
+
+// Line is a line of a diff with its operation
+type Line struct {
+	Op   byte
+	Text string
+}
+
+// Lines computes a line-based diff turning a into b
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+
+	// Common prefix and suffix are equal without computing anything
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(x)+len(y)-prefix-suffix)
+	for _, text := range x[:prefix] {
+		lines = append(lines, Line{EQUAL, text})
+	}
+	lines = append(lines, lcs(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, text := range x[len(x)-suffix:] {
+		lines = append(lines, Line{EQUAL, text})
+	}
+
+	return lines
+}
+
+// Unified formats a diff as a unified diff with the given number of context lines,
+// it is empty when there are no changes
+func Unified(from, to string, lines []Line, context int) string {
+	type hunk struct{ start, end int }
+
+	var hunks []hunk
+	for i, l := range lines {
+		if l.Op == EQUAL {
+			continue
+		}
+
+		start, end := i-context, i+1+context
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if n := len(hunks); n > 0 && start <= hunks[n-1].end {
+			hunks[n-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunk{start, end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// Line numbers in a and b before each diff line
+	aLine, bLine := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	for i, l := range lines {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if l.Op != INSERT {
+			aLine[i+1]++
+		}
+		if l.Op != DELETE {
+			bLine[i+1]++
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", from, to)
+	for _, h := range hunks {
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(aLine[h.start], aLine[h.end]), hunkRange(bLine[h.start], bLine[h.end]))
+		for _, l := range lines[h.start:h.end] {
+			buf.WriteByte(l.Op)
+			buf.WriteString(l.Text)
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.String()
+}
+
+// hunkRange formats the lines after start up to end, an empty range naming the line before it
+func hunkRange(start, end int) string {
+	if end-start == 1 {
+		return fmt.Sprintf("%d", end)
+	}
+	if end == start {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
+
+func split(s string) []string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lcs diffs x and y through their longest common subsequence
+func lcs(x, y []string) []Line {
+	lines := make([]Line, 0, len(x)+len(y))
+	if len(x) == 0 || len(y) == 0 || len(x)*len(y) > maxCells {
+		for _, text := range x {
+			lines = append(lines, Line{DELETE, text})
+		}
+		for _, text := range y {
+			lines = append(lines, Line{INSERT, text})
+		}
+		return lines
+	}
+
+	// table[i*(m+1)+j] is the LCS length of x[i:] and y[j:]
+	n, m := len(x), len(y)
+	table := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				table[i*(m+1)+j] = table[(i+1)*(m+1)+j+1] + 1
+			} else if down, right := table[(i+1)*(m+1)+j], table[i*(m+1)+j+1]; down >= right {
+				table[i*(m+1)+j] = down
+			} else {
+				table[i*(m+1)+j] = right
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, Line{EQUAL, x[i]})
+			i++
+			j++
+		case table[(i+1)*(m+1)+j] >= table[i*(m+1)+j+1]:
+			lines = append(lines, Line{DELETE, x[i]})
+			i++
+		default:
+			lines = append(lines, Line{INSERT, y[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, Line{DELETE, x[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, Line{INSERT, y[j]})
+	}
+
+	return lines
+}
@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{"both empty", "", "", []Line{}},
+		{"equal", "a\nb\n", "a\nb", []Line{{EQUAL, "a"}, {EQUAL, "b"}}},
+		{"crlf", "a\r\nb\r\n", "a\nb\n", []Line{{EQUAL, "a"}, {EQUAL, "b"}}},
+		{"from empty", "", "a\nb\n", []Line{{INSERT, "a"}, {INSERT, "b"}}},
+		{"to empty", "a\nb\n", "", []Line{{DELETE, "a"}, {DELETE, "b"}}},
+		{"insert", "a\nc\n", "a\nb\nc\n", []Line{{EQUAL, "a"}, {INSERT, "b"}, {EQUAL, "c"}}},
+		{"delete", "a\nb\nc\n", "a\nc\n", []Line{{EQUAL, "a"}, {DELETE, "b"}, {EQUAL, "c"}}},
+		{"replace", "a\nb\nc\n", "a\nB\nc\n", []Line{{EQUAL, "a"}, {DELETE, "b"}, {INSERT, "B"}, {EQUAL, "c"}}},
+		{"move", "a\nb\nc\n", "b\nc\na\n", []Line{{DELETE, "a"}, {EQUAL, "b"}, {EQUAL, "c"}, {INSERT, "a"}}},
+	}
+	for _, tt := range tests {
+		if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Lines() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinesTooLarge(t *testing.T) {
+	// Past maxCells the changed middle is a full replacement, the common prefix stays equal
+	n := 1<<11 + 1
+	a := "same\n" + strings.Repeat("a\n", n)
+	b := "same\n" + strings.Repeat("b\n", n)
+
+	lines := Lines(a, b)
+	if len(lines) != 1+2*n {
+		t.Fatalf("Lines() = %d lines, want %d", len(lines), 1+2*n)
+	}
+	if lines[0] != (Line{EQUAL, "same"}) || lines[1] != (Line{DELETE, "a"}) || lines[len(lines)-1] != (Line{INSERT, "b"}) {
+		t.Errorf("Lines() = %v ... %v, want the prefix, then deletes, then inserts", lines[:2], lines[len(lines)-1])
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{"no changes", "a\nb\n", "a\nb\n", 3, ""},
+		{"both empty", "", "", 3, ""},
+		{"from empty", "", "a\nb\n", 3, "@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"to empty", "a\nb\n", "", 3, "@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"single line added", "", "a\n", 3, "@@ -0,0 +1 @@\n+a\n"},
+		{"insert only", "a\nb\nc\n", "a\nb\nX\nc\n", 0, "@@ -2,0 +3 @@\n+X\n"},
+		{"delete only", "a\nb\nc\n", "a\nc\n", 0, "@@ -2 +1,0 @@\n-b\n"},
+		{"insert with context", "a\nb\nc\n", "a\nb\nX\nc\n", 1, "@@ -2,2 +2,3 @@\n b\n+X\n c\n"},
+		{"replace", "a\nb\nc\n", "a\nB\nc\n", 3, "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"},
+		{
+			"separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n",
+			"one\n2\n3\n4\n5\n6\n7\neight\n",
+			1,
+			"@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -7,2 +7,2 @@\n 7\n-8\n+eight\n",
+		},
+		{
+			// Changes at most twice the context apart share a hunk
+			"merged hunks",
+			"1\n2\n3\n4\n",
+			"one\n2\n3\nfour\n",
+			1,
+			"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n-4\n+four\n",
+		},
+	}
+	for _, tt := range tests {
+		want := tt.want
+		if want != "" {
+			want = "--- a\n+++ b\n" + want
+		}
+		if got := Unified("a", "b", Lines(tt.a, tt.b), tt.context); got != want {
+			t.Errorf("%s: Unified() =\n%s\nwant\n%s", tt.name, got, want)
+		}
+	}
+}
@@ -3,4 +3,5 @@ package e
 const (
 	CACHE_ARTICLE = "ARTICLE"
 	CACHE_TAG     = "TAG"
+	CACHE_FEED    = "FEED"
 )
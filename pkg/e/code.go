@@ -16,20 +16,118 @@ const (
 	ERROR_EXPORT_TAG_FAIL = 10009
 	ERROR_IMPORT_TAG_FAIL = 10010
 
-	ERROR_NOT_EXIST_ARTICLE        = 10011
-	ERROR_CHECK_EXIST_ARTICLE_FAIL = 10012
-	ERROR_ADD_ARTICLE_FAIL         = 10013
-	ERROR_DELETE_ARTICLE_FAIL      = 10014
-	ERROR_EDIT_ARTICLE_FAIL        = 10015
-	ERROR_COUNT_ARTICLE_FAIL       = 10016
-	ERROR_GET_ARTICLES_FAIL        = 10017
-	ERROR_GET_ARTICLE_FAIL         = 10018
-	ERROR_GEN_ARTICLE_POSTER_FAIL  = 10019
-
-	ERROR_AUTH_CHECK_TOKEN_FAIL    = 20001
-	ERROR_AUTH_CHECK_TOKEN_TIMEOUT = 20002
-	ERROR_AUTH_TOKEN               = 20003
-	ERROR_AUTH                     = 20004
+	ERROR_NOT_EXIST_ARTICLE             = 10011
+	ERROR_CHECK_EXIST_ARTICLE_FAIL      = 10012
+	ERROR_ADD_ARTICLE_FAIL              = 10013
+	ERROR_DELETE_ARTICLE_FAIL           = 10014
+	ERROR_EDIT_ARTICLE_FAIL             = 10015
+	ERROR_COUNT_ARTICLE_FAIL            = 10016
+	ERROR_GET_ARTICLES_FAIL             = 10017
+	ERROR_GET_ARTICLE_FAIL              = 10018
+	ERROR_GEN_ARTICLE_POSTER_FAIL       = 10019
+	ERROR_GET_ARTICLE_AUTHORS_FAIL      = 10020
+	ERROR_NOT_EXIST_ARTICLE_AUTHOR      = 10021
+	ERROR_EDIT_ARTICLE_AUTHOR_FAIL      = 10022
+	ERROR_DELETE_ARTICLE_AUTHOR_FAIL    = 10023
+	ERROR_ARTICLE_AUTHOR_CREATOR        = 10024
+	ERROR_GET_ARTICLE_REVISIONS_FAIL    = 10025
+	ERROR_COUNT_ARTICLE_REVISION_FAIL   = 10026
+	ERROR_NOT_EXIST_ARTICLE_REVISION    = 10027
+	ERROR_GET_ARTICLE_REVISION_FAIL     = 10028
+	ERROR_RESTORE_ARTICLE_REVISION_FAIL = 10029
+	ERROR_ARTICLE_TRANSITION_INVALID    = 10030
+	ERROR_ARTICLE_PUBLISH_AT_INVALID    = 10031
+	ERROR_ARTICLE_STATUS_CHANGED        = 10032
+	ERROR_EDIT_ARTICLE_STATUS_FAIL      = 10033
+	ERROR_EXIST_ARTICLE_SLUG            = 10034
+	ERROR_CHECK_EXIST_ARTICLE_SLUG_FAIL = 10035
+	ERROR_RESOLVE_ARTICLE_SLUG_FAIL     = 10036
+	ERROR_SEARCH_ARTICLES_FAIL          = 10037
+	ERROR_ARTICLE_CONTENT_UNSAFE        = 10038
+	ERROR_GET_POPULAR_ARTICLES_FAIL     = 10039
+	ERROR_GET_FEED_FAIL                 = 10040
+
+	ERROR_GET_TRASH_FAIL            = 10101
+	ERROR_COUNT_TRASH_FAIL          = 10102
+	ERROR_NOT_EXIST_TRASHED_ARTICLE = 10103
+	ERROR_NOT_EXIST_TRASHED_TAG     = 10104
+	ERROR_RESTORE_TRASH_FAIL        = 10105
+	ERROR_PURGE_TRASH_FAIL          = 10106
+
+	ERROR_GET_COMMENTS_FAIL          = 10201
+	ERROR_COUNT_COMMENT_FAIL         = 10202
+	ERROR_NOT_EXIST_COMMENT          = 10203
+	ERROR_ADD_COMMENT_FAIL           = 10204
+	ERROR_COMMENT_PARENT_INVALID     = 10205
+	ERROR_ARTICLE_COMMENTS_CLOSED    = 10206
+	ERROR_MODERATE_COMMENTS_FAIL     = 10207
+	ERROR_DELETE_COMMENT_FAIL        = 10208
+	ERROR_EDIT_ARTICLE_COMMENTS_FAIL = 10209
+
+	ERROR_AUTH_CHECK_TOKEN_FAIL          = 20001
+	ERROR_AUTH_CHECK_TOKEN_TIMEOUT       = 20002
+	ERROR_AUTH_TOKEN                     = 20003
+	ERROR_AUTH                           = 20004
+	ERROR_GET_SESSIONS_FAIL              = 20005
+	ERROR_NOT_EXIST_SESSION              = 20006
+	ERROR_DELETE_SESSION_FAIL            = 20007
+	ERROR_AUTH_UNSUPPORTED_GRANT_TYPE    = 20008
+	ERROR_AUTH_REFRESH_TOKEN_INVALID     = 20009
+	ERROR_AUTH_REFRESH_TOKEN_REUSED      = 20010
+	ERROR_AUTH_SESSION_STORE_UNAVAILABLE = 20011
+	ERROR_AUTH_PERMISSION_DENIED         = 20012
+	ERROR_AUTH_DISABLED                  = 20013
+	ERROR_AUTH_OLD_PASSWORD              = 20014
+	ERROR_AUTH_LOCKED                    = 20015
+	ERROR_AUTH_MFA_TOKEN_INVALID         = 20016
+	ERROR_AUTH_MFA_CODE_INVALID          = 20017
+	ERROR_AUTH_API_KEY_INVALID           = 20018
+	ERROR_AUTH_API_KEY_EXPIRED           = 20019
+	ERROR_AUTH_SESSION_REQUIRED          = 20020
+	ERROR_AUTH_CLIENT_INVALID            = 20021
+	ERROR_AUTH_SCOPE_INVALID             = 20022
+	ERROR_AUTH_RESET_TOKEN_INVALID       = 20023
+	ERROR_AUTH_RESET_THROTTLED           = 20024
+
+	ERROR_EXIST_USER           = 20101
+	ERROR_EXIST_USER_FAIL      = 20102
+	ERROR_NOT_EXIST_USER       = 20103
+	ERROR_EXIST_EMAIL          = 20104
+	ERROR_GET_USERS_FAIL       = 20105
+	ERROR_COUNT_USER_FAIL      = 20106
+	ERROR_GET_USER_FAIL        = 20107
+	ERROR_ADD_USER_FAIL        = 20108
+	ERROR_EDIT_USER_FAIL       = 20109
+	ERROR_DELETE_USER_FAIL     = 20110
+	ERROR_EDIT_SELF_USER_FAIL  = 20111
+	ERROR_UNLOCK_USER_FAIL     = 20112
+	ERROR_TOTP_ALREADY_ENABLED = 20113
+	ERROR_TOTP_NOT_ENABLED     = 20114
+	ERROR_TOTP_NOT_ENROLLED    = 20115
+	ERROR_TOTP_ENROLL_FAIL     = 20116
+	ERROR_TOTP_CONFIRM_FAIL    = 20117
+	ERROR_TOTP_DISABLE_FAIL    = 20118
+	ERROR_RESET_PASSWORD_FAIL  = 20119
+	ERROR_PASSWORD_TOO_SHORT   = 20120
+	ERROR_PASSWORD_TOO_WEAK    = 20121
+	ERROR_PASSWORD_BREACHED    = 20122
+	ERROR_PASSWORD_REUSED      = 20123
+
+	ERROR_ADD_API_KEY_FAIL      = 20201
+	ERROR_GET_API_KEYS_FAIL     = 20202
+	ERROR_NOT_EXIST_API_KEY     = 20203
+	ERROR_DELETE_API_KEY_FAIL   = 20204
+	ERROR_API_KEY_SCOPE_INVALID = 20205
+
+	ERROR_ADD_OAUTH_CLIENT_FAIL      = 20301
+	ERROR_GET_OAUTH_CLIENTS_FAIL     = 20302
+	ERROR_NOT_EXIST_OAUTH_CLIENT     = 20303
+	ERROR_DELETE_OAUTH_CLIENT_FAIL   = 20304
+	ERROR_OAUTH_CLIENT_SCOPE_INVALID = 20305
+
+	ERROR_GET_AUTH_EVENTS_FAIL    = 20401
+	ERROR_COUNT_AUTH_EVENT_FAIL   = 20402
+	ERROR_EXPORT_AUTH_EVENTS_FAIL = 20403
 
 	ERROR_UPLOAD_SAVE_IMAGE_FAIL    = 30001
 	ERROR_UPLOAD_CHECK_IMAGE_FAIL   = 30002
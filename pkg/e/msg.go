@@ -1,35 +1,127 @@
 package e
 
 var MsgFlags = map[int]string{
-	SUCCESS:                         "ok",
-	ERROR:                           "fail",
-	INVALID_PARAMS:                  "Invalid parameters",
-	ERROR_EXIST_TAG:                 "Tag name already exists",
-	ERROR_EXIST_TAG_FAIL:            "Failed to get existing tag",
-	ERROR_NOT_EXIST_TAG:             "Tag does not exist",
-	ERROR_GET_TAGS_FAIL:             "Failed to get all tags",
-	ERROR_COUNT_TAG_FAIL:            "Failed to count tags",
-	ERROR_ADD_TAG_FAIL:              "Failed to add tag",
-	ERROR_EDIT_TAG_FAIL:             "Failed to modify tag",
-	ERROR_DELETE_TAG_FAIL:           "Failed to delete tag",
-	ERROR_EXPORT_TAG_FAIL:           "Failed to export tag",
-	ERROR_IMPORT_TAG_FAIL:           "Failed to import tag",
-	ERROR_NOT_EXIST_ARTICLE:         "Article does not exist",
-	ERROR_ADD_ARTICLE_FAIL:          "Failed to add article",
-	ERROR_DELETE_ARTICLE_FAIL:       "Failed to delete article",
-	ERROR_CHECK_EXIST_ARTICLE_FAIL:  "Failed to check if article exists",
-	ERROR_EDIT_ARTICLE_FAIL:         "Failed to modify article",
-	ERROR_COUNT_ARTICLE_FAIL:        "Failed to count articles",
-	ERROR_GET_ARTICLES_FAIL:         "Failed to get multiple articles",
-	ERROR_GET_ARTICLE_FAIL:          "Failed to get article",
-	ERROR_GEN_ARTICLE_POSTER_FAIL:   "Failed to generate article poster",
-	ERROR_AUTH_CHECK_TOKEN_FAIL:     "Token authentication failed",
-	ERROR_AUTH_CHECK_TOKEN_TIMEOUT:  "Token has expired",
-	ERROR_AUTH_TOKEN:                "Failed to generate token",
-	ERROR_AUTH:                      "Invalid username or password",
-	ERROR_UPLOAD_SAVE_IMAGE_FAIL:    "Failed to save image",
-	ERROR_UPLOAD_CHECK_IMAGE_FAIL:   "Failed to check image",
-	ERROR_UPLOAD_CHECK_IMAGE_FORMAT: "Image validation error, problem with format or size",
+	SUCCESS:                              "ok",
+	ERROR:                                "fail",
+	INVALID_PARAMS:                       "Invalid parameters",
+	ERROR_EXIST_TAG:                      "Tag name already exists",
+	ERROR_EXIST_TAG_FAIL:                 "Failed to get existing tag",
+	ERROR_NOT_EXIST_TAG:                  "Tag does not exist",
+	ERROR_GET_TAGS_FAIL:                  "Failed to get all tags",
+	ERROR_COUNT_TAG_FAIL:                 "Failed to count tags",
+	ERROR_ADD_TAG_FAIL:                   "Failed to add tag",
+	ERROR_EDIT_TAG_FAIL:                  "Failed to modify tag",
+	ERROR_DELETE_TAG_FAIL:                "Failed to delete tag",
+	ERROR_EXPORT_TAG_FAIL:                "Failed to export tag",
+	ERROR_IMPORT_TAG_FAIL:                "Failed to import tag",
+	ERROR_NOT_EXIST_ARTICLE:              "Article does not exist",
+	ERROR_ADD_ARTICLE_FAIL:               "Failed to add article",
+	ERROR_DELETE_ARTICLE_FAIL:            "Failed to delete article",
+	ERROR_CHECK_EXIST_ARTICLE_FAIL:       "Failed to check if article exists",
+	ERROR_EDIT_ARTICLE_FAIL:              "Failed to modify article",
+	ERROR_COUNT_ARTICLE_FAIL:             "Failed to count articles",
+	ERROR_GET_ARTICLES_FAIL:              "Failed to get multiple articles",
+	ERROR_GET_ARTICLE_FAIL:               "Failed to get article",
+	ERROR_GEN_ARTICLE_POSTER_FAIL:        "Failed to generate article poster",
+	ERROR_GET_ARTICLE_AUTHORS_FAIL:       "Failed to get article co-authors",
+	ERROR_NOT_EXIST_ARTICLE_AUTHOR:       "User is not a co-author of the article",
+	ERROR_EDIT_ARTICLE_AUTHOR_FAIL:       "Failed to set article co-author",
+	ERROR_DELETE_ARTICLE_AUTHOR_FAIL:     "Failed to remove article co-author",
+	ERROR_ARTICLE_AUTHOR_CREATOR:         "The creator of an article is always its owner",
+	ERROR_GET_ARTICLE_REVISIONS_FAIL:     "Failed to get article revisions",
+	ERROR_COUNT_ARTICLE_REVISION_FAIL:    "Failed to count article revisions",
+	ERROR_NOT_EXIST_ARTICLE_REVISION:     "Article revision does not exist",
+	ERROR_GET_ARTICLE_REVISION_FAIL:      "Failed to get article revision",
+	ERROR_RESTORE_ARTICLE_REVISION_FAIL:  "Failed to restore article revision",
+	ERROR_ARTICLE_TRANSITION_INVALID:     "Article cannot move from its current status to the requested one",
+	ERROR_ARTICLE_PUBLISH_AT_INVALID:     "Scheduled articles need a publish_at in the future",
+	ERROR_ARTICLE_STATUS_CHANGED:         "Article status was changed by another request",
+	ERROR_EDIT_ARTICLE_STATUS_FAIL:       "Failed to change article status",
+	ERROR_EXIST_ARTICLE_SLUG:             "Slug is used by another article",
+	ERROR_CHECK_EXIST_ARTICLE_SLUG_FAIL:  "Failed to check if slug exists",
+	ERROR_RESOLVE_ARTICLE_SLUG_FAIL:      "Failed to look up article by slug",
+	ERROR_SEARCH_ARTICLES_FAIL:           "Failed to search articles",
+	ERROR_ARTICLE_CONTENT_UNSAFE:         "Article content contains unsafe HTML",
+	ERROR_GET_POPULAR_ARTICLES_FAIL:      "Failed to get popular articles",
+	ERROR_GET_FEED_FAIL:                  "Failed to get feed",
+	ERROR_GET_TRASH_FAIL:                 "Failed to get deleted items",
+	ERROR_COUNT_TRASH_FAIL:               "Failed to count deleted items",
+	ERROR_NOT_EXIST_TRASHED_ARTICLE:      "Article is not in the trash",
+	ERROR_NOT_EXIST_TRASHED_TAG:          "Tag is not in the trash",
+	ERROR_RESTORE_TRASH_FAIL:             "Failed to restore deleted item",
+	ERROR_PURGE_TRASH_FAIL:               "Failed to permanently delete item",
+	ERROR_GET_COMMENTS_FAIL:              "Failed to get comments",
+	ERROR_COUNT_COMMENT_FAIL:             "Failed to count comments",
+	ERROR_NOT_EXIST_COMMENT:              "Comment does not exist",
+	ERROR_ADD_COMMENT_FAIL:               "Failed to add comment",
+	ERROR_COMMENT_PARENT_INVALID:         "The comment replied to is not an approved comment of the article",
+	ERROR_ARTICLE_COMMENTS_CLOSED:        "Comments are closed on this article",
+	ERROR_MODERATE_COMMENTS_FAIL:         "Failed to moderate comments",
+	ERROR_DELETE_COMMENT_FAIL:            "Failed to delete comment",
+	ERROR_EDIT_ARTICLE_COMMENTS_FAIL:     "Failed to open or close comments",
+	ERROR_AUTH_CHECK_TOKEN_FAIL:          "Token authentication failed",
+	ERROR_AUTH_CHECK_TOKEN_TIMEOUT:       "Token has expired",
+	ERROR_AUTH_TOKEN:                     "Failed to generate token",
+	ERROR_AUTH:                           "Invalid username or password",
+	ERROR_GET_SESSIONS_FAIL:              "Failed to get sessions",
+	ERROR_NOT_EXIST_SESSION:              "Session does not exist",
+	ERROR_DELETE_SESSION_FAIL:            "Failed to revoke session",
+	ERROR_AUTH_UNSUPPORTED_GRANT_TYPE:    "Unsupported grant type",
+	ERROR_AUTH_REFRESH_TOKEN_INVALID:     "Refresh token is invalid or expired",
+	ERROR_AUTH_REFRESH_TOKEN_REUSED:      "Refresh token reuse detected, all related sessions have been revoked",
+	ERROR_AUTH_SESSION_STORE_UNAVAILABLE: "Session store is unavailable",
+	ERROR_AUTH_PERMISSION_DENIED:         "Permission denied",
+	ERROR_AUTH_DISABLED:                  "Account is disabled",
+	ERROR_AUTH_OLD_PASSWORD:              "Old password is incorrect",
+	ERROR_AUTH_LOCKED:                    "Too many failed login attempts, try again later",
+	ERROR_AUTH_MFA_TOKEN_INVALID:         "MFA token is invalid or has expired",
+	ERROR_AUTH_MFA_CODE_INVALID:          "Verification code is invalid",
+	ERROR_AUTH_API_KEY_INVALID:           "API key is invalid",
+	ERROR_AUTH_API_KEY_EXPIRED:           "API key has expired",
+	ERROR_AUTH_SESSION_REQUIRED:          "This endpoint requires a login session, API keys and client tokens are not accepted",
+	ERROR_AUTH_CLIENT_INVALID:            "Client authentication failed",
+	ERROR_AUTH_SCOPE_INVALID:             "Requested scope is not allowed for the client",
+	ERROR_AUTH_RESET_TOKEN_INVALID:       "Password reset token is invalid or has expired",
+	ERROR_AUTH_RESET_THROTTLED:           "Too many password reset requests, try again later",
+	ERROR_EXIST_USER:                     "Username already exists",
+	ERROR_EXIST_USER_FAIL:                "Failed to check if user exists",
+	ERROR_NOT_EXIST_USER:                 "User does not exist",
+	ERROR_EXIST_EMAIL:                    "Email already in use",
+	ERROR_GET_USERS_FAIL:                 "Failed to get users",
+	ERROR_COUNT_USER_FAIL:                "Failed to count users",
+	ERROR_GET_USER_FAIL:                  "Failed to get user",
+	ERROR_ADD_USER_FAIL:                  "Failed to add user",
+	ERROR_EDIT_USER_FAIL:                 "Failed to modify user",
+	ERROR_DELETE_USER_FAIL:               "Failed to delete user",
+	ERROR_EDIT_SELF_USER_FAIL:            "Administrators cannot disable, demote or delete themselves",
+	ERROR_UNLOCK_USER_FAIL:               "Failed to unlock user",
+	ERROR_TOTP_ALREADY_ENABLED:           "Two-factor authentication is already enabled",
+	ERROR_TOTP_NOT_ENABLED:               "Two-factor authentication is not enabled",
+	ERROR_TOTP_NOT_ENROLLED:              "Two-factor authentication enrollment was not started",
+	ERROR_TOTP_ENROLL_FAIL:               "Failed to start two-factor authentication enrollment",
+	ERROR_TOTP_CONFIRM_FAIL:              "Failed to enable two-factor authentication",
+	ERROR_TOTP_DISABLE_FAIL:              "Failed to disable two-factor authentication",
+	ERROR_RESET_PASSWORD_FAIL:            "Failed to reset password",
+	ERROR_PASSWORD_TOO_SHORT:             "Password is too short",
+	ERROR_PASSWORD_TOO_WEAK:              "Password must mix more of lower case, upper case, digits and symbols",
+	ERROR_PASSWORD_BREACHED:              "Password is known from data breaches, choose another one",
+	ERROR_PASSWORD_REUSED:                "Password was used recently, choose another one",
+	ERROR_ADD_API_KEY_FAIL:               "Failed to create API key",
+	ERROR_GET_API_KEYS_FAIL:              "Failed to get API keys",
+	ERROR_NOT_EXIST_API_KEY:              "API key does not exist",
+	ERROR_DELETE_API_KEY_FAIL:            "Failed to revoke API key",
+	ERROR_API_KEY_SCOPE_INVALID:          "API key scopes must be permissions of your role",
+	ERROR_ADD_OAUTH_CLIENT_FAIL:          "Failed to register OAuth2 client",
+	ERROR_GET_OAUTH_CLIENTS_FAIL:         "Failed to get OAuth2 clients",
+	ERROR_NOT_EXIST_OAUTH_CLIENT:         "OAuth2 client does not exist",
+	ERROR_DELETE_OAUTH_CLIENT_FAIL:       "Failed to delete OAuth2 client",
+	ERROR_OAUTH_CLIENT_SCOPE_INVALID:     "OAuth2 client scopes must be known permissions",
+	ERROR_GET_AUTH_EVENTS_FAIL:           "Failed to get auth events",
+	ERROR_COUNT_AUTH_EVENT_FAIL:          "Failed to count auth events",
+	ERROR_EXPORT_AUTH_EVENTS_FAIL:        "Failed to export auth events",
+	ERROR_UPLOAD_SAVE_IMAGE_FAIL:         "Failed to save image",
+	ERROR_UPLOAD_CHECK_IMAGE_FAIL:        "Failed to check image",
+	ERROR_UPLOAD_CHECK_IMAGE_FORMAT:      "Image validation error, problem with format or size",
 }
 
 // GetMsg get error information based on Code
@@ -0,0 +1,65 @@
+package feed
+
+import (
+	"fmt"
+	"time"
+)
+
+// Formats a feed can be rendered in
+const (
+	FORMAT_RSS  = "rss"
+	FORMAT_ATOM = "atom"
+	FORMAT_JSON = "json"
+)
+
+// Feed is a list of entries of a site. Links are absolute.
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the site the feed belongs to and FeedUrl where the feed itself is served
+	Link    string
+	FeedUrl string
+	// Updated is when the latest entry changed
+	Updated time.Time
+	Items   []Item
+}
+
+// Item is an entry of a feed
+type Item struct {
+	// ID identifies the entry for good, unlike Link it never changes
+	ID          string
+	Title       string
+	Link        string
+	Summary     string
+	ContentHtml string
+	Author      string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// ContentType is the media type of a feed rendered in the format
+func ContentType(format string) string {
+	switch format {
+	case FORMAT_RSS:
+		return "application/rss+xml; charset=utf-8"
+	case FORMAT_ATOM:
+		return "application/atom+xml; charset=utf-8"
+	default:
+		return "application/feed+json; charset=utf-8"
+	}
+}
+
+// Render renders the feed in the format
+func (f *Feed) Render(format string) ([]byte, error) {
+	switch format {
+	case FORMAT_RSS:
+		return f.RSS()
+	case FORMAT_ATOM:
+		return f.Atom()
+	case FORMAT_JSON:
+		return f.JSON()
+	default:
+		return nil, fmt.Errorf("feed: unknown format %q", format)
+	}
+}
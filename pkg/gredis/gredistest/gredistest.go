@@ -0,0 +1,415 @@
+// Package gredistest provides an in-memory Redis for tests of code using gredis. It understands
+// the commands gredis sends and can be taken down to simulate an unreachable Redis.
+package gredistest
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+)
+
+// ErrDown is returned by every command while the server is down
+var ErrDown = errors.New("gredistest: connection refused")
+
+// Server is an in-memory Redis satisfying gredis.Pool
+type Server struct {
+	mu      sync.Mutex
+	down    bool
+	strings map[string][]byte
+	sets    map[string]map[string]bool
+	hashes  map[string]map[string]int
+	zsets   map[string]map[string]float64
+	expires map[string]time.Time
+}
+
+// NewServer returns an empty server
+func NewServer() *Server {
+	return &Server{
+		strings: make(map[string][]byte),
+		sets:    make(map[string]map[string]bool),
+		hashes:  make(map[string]map[string]int),
+		zsets:   make(map[string]map[string]float64),
+		expires: make(map[string]time.Time),
+	}
+}
+
+// Use makes gredis talk to a new server until the test ends
+func Use(t testing.TB) *Server {
+	s := NewServer()
+	old := gredis.RedisConn
+	gredis.RedisConn = s
+	t.Cleanup(func() {
+		gredis.RedisConn = old
+	})
+
+	return s
+}
+
+// SetDown takes the server down, failing every command with ErrDown, or brings it back up
+func (s *Server) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+}
+
+// Get hands out a connection to the server
+func (s *Server) Get() redis.Conn {
+	return &conn{s: s}
+}
+
+// Keys returns the live keys matching a pattern, sorted
+func (s *Server) Keys(pattern string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.keys(pattern)
+}
+
+type conn struct {
+	s *Server
+}
+
+func (c *conn) Close() error { return nil }
+func (c *conn) Err() error   { return nil }
+func (c *conn) Send(cmd string, args ...interface{}) error {
+	return errors.New("gredistest: pipelining is not supported")
+}
+func (c *conn) Flush() error { return nil }
+func (c *conn) Receive() (interface{}, error) {
+	return nil, errors.New("gredistest: pipelining is not supported")
+}
+func (c *conn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	return c.s.do(strings.ToUpper(cmd), toStrings(args))
+}
+
+func (s *Server) do(cmd string, args []string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		return nil, ErrDown
+	}
+	for _, key := range args[:min(len(args), 1)] {
+		s.expire(key)
+	}
+
+	switch cmd {
+	case "PING":
+		return "PONG", nil
+	case "SET":
+		return s.set(args)
+	case "GET":
+		if v, ok := s.strings[args[0]]; ok {
+			return v, nil
+		}
+		return nil, nil
+	case "EXISTS":
+		return boolInt(s.exists(args[0])), nil
+	case "DEL":
+		n := 0
+		for _, key := range args {
+			s.expire(key)
+			if s.exists(key) {
+				s.del(key)
+				n++
+			}
+		}
+		return int64(n), nil
+	case "KEYS":
+		var reply []interface{}
+		for _, key := range s.keys(args[0]) {
+			reply = append(reply, []byte(key))
+		}
+		return reply, nil
+	case "EXPIRE":
+		if !s.exists(args[0]) {
+			return int64(0), nil
+		}
+		seconds, _ := strconv.Atoi(args[1])
+		s.expires[args[0]] = time.Now().Add(time.Duration(seconds) * time.Second)
+		return int64(1), nil
+	case "TTL":
+		if !s.exists(args[0]) {
+			return int64(-2), nil
+		}
+		at, ok := s.expires[args[0]]
+		if !ok {
+			return int64(-1), nil
+		}
+		return int64((time.Until(at) + time.Second - 1) / time.Second), nil
+	case "INCR":
+		n, _ := strconv.Atoi(string(s.strings[args[0]]))
+		n++
+		s.strings[args[0]] = []byte(strconv.Itoa(n))
+		return int64(n), nil
+	case "RENAME":
+		return s.rename(args[0], args[1])
+	case "SADD":
+		if s.sets[args[0]] == nil {
+			s.sets[args[0]] = make(map[string]bool)
+		}
+		for _, m := range args[1:] {
+			s.sets[args[0]][m] = true
+		}
+		return int64(len(args) - 1), nil
+	case "SREM":
+		for _, m := range args[1:] {
+			delete(s.sets[args[0]], m)
+		}
+		if len(s.sets[args[0]]) == 0 {
+			delete(s.sets, args[0])
+		}
+		return int64(len(args) - 1), nil
+	case "SMEMBERS":
+		members := make([]string, 0, len(s.sets[args[0]]))
+		for m := range s.sets[args[0]] {
+			members = append(members, m)
+		}
+		sort.Strings(members)
+		return byteReplies(members), nil
+	case "HINCRBY":
+		n, _ := strconv.Atoi(args[2])
+		if s.hashes[args[0]] == nil {
+			s.hashes[args[0]] = make(map[string]int)
+		}
+		s.hashes[args[0]][args[1]] += n
+		return int64(s.hashes[args[0]][args[1]]), nil
+	case "HDEL":
+		n := 0
+		for _, field := range args[1:] {
+			if _, ok := s.hashes[args[0]][field]; ok {
+				delete(s.hashes[args[0]], field)
+				n++
+			}
+		}
+		if len(s.hashes[args[0]]) == 0 {
+			delete(s.hashes, args[0])
+		}
+		return int64(n), nil
+	case "HGETALL":
+		var reply []string
+		for field, n := range s.hashes[args[0]] {
+			reply = append(reply, field, strconv.Itoa(n))
+		}
+		return byteReplies(reply), nil
+	case "ZINCRBY":
+		n, _ := strconv.ParseFloat(args[1], 64)
+		s.zset(args[0])[args[2]] += n
+		return []byte(formatScore(s.zsets[args[0]][args[2]])), nil
+	case "ZADD":
+		for i := 1; i+1 < len(args); i += 2 {
+			n, _ := strconv.ParseFloat(args[i], 64)
+			s.zset(args[0])[args[i+1]] = n
+		}
+		return int64((len(args) - 1) / 2), nil
+	case "ZUNIONSTORE":
+		n, _ := strconv.Atoi(args[1])
+		union := make(map[string]float64)
+		for _, key := range args[2 : 2+n] {
+			s.expire(key)
+			for m, score := range s.zsets[key] {
+				union[m] += score
+			}
+		}
+		s.del(args[0])
+		if len(union) > 0 {
+			s.zsets[args[0]] = union
+		}
+		return int64(len(union)), nil
+	case "ZREVRANGE":
+		return s.zrevrange(args)
+	default:
+		return nil, fmt.Errorf("gredistest: unsupported command %s", cmd)
+	}
+}
+
+func (s *Server) set(args []string) (interface{}, error) {
+	key, value := args[0], args[1]
+	var ttl time.Duration
+	nx := false
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "EX":
+			i++
+			seconds, _ := strconv.Atoi(args[i])
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+	if nx && s.exists(key) {
+		return nil, nil
+	}
+
+	s.del(key)
+	s.strings[key] = []byte(value)
+	if ttl > 0 {
+		s.expires[key] = time.Now().Add(ttl)
+	}
+	return "OK", nil
+}
+
+func (s *Server) rename(key, newKey string) (interface{}, error) {
+	if !s.exists(key) {
+		return nil, redis.Error("ERR no such key")
+	}
+
+	s.del(newKey)
+	if v, ok := s.strings[key]; ok {
+		s.strings[newKey] = v
+	}
+	if v, ok := s.sets[key]; ok {
+		s.sets[newKey] = v
+	}
+	if v, ok := s.hashes[key]; ok {
+		s.hashes[newKey] = v
+	}
+	if v, ok := s.zsets[key]; ok {
+		s.zsets[newKey] = v
+	}
+	if at, ok := s.expires[key]; ok {
+		s.expires[newKey] = at
+	}
+	s.del(key)
+	return "OK", nil
+}
+
+func (s *Server) zrevrange(args []string) (interface{}, error) {
+	type member struct {
+		name  string
+		score float64
+	}
+	members := make([]member, 0, len(s.zsets[args[0]]))
+	for m, score := range s.zsets[args[0]] {
+		members = append(members, member{m, score})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].score != members[j].score {
+			return members[i].score > members[j].score
+		}
+		return members[i].name > members[j].name
+	})
+
+	start, _ := strconv.Atoi(args[1])
+	stop, _ := strconv.Atoi(args[2])
+	if stop < 0 || stop >= len(members) {
+		stop = len(members) - 1
+	}
+	withScores := len(args) > 3 && strings.ToUpper(args[3]) == "WITHSCORES"
+
+	var reply []string
+	for i := start; i <= stop; i++ {
+		reply = append(reply, members[i].name)
+		if withScores {
+			reply = append(reply, formatScore(members[i].score))
+		}
+	}
+	return byteReplies(reply), nil
+}
+
+func (s *Server) zset(key string) map[string]float64 {
+	if s.zsets[key] == nil {
+		s.zsets[key] = make(map[string]float64)
+	}
+
+	return s.zsets[key]
+}
+
+func (s *Server) exists(key string) bool {
+	_, str := s.strings[key]
+	_, set := s.sets[key]
+	_, hash := s.hashes[key]
+	_, zset := s.zsets[key]
+
+	return str || set || hash || zset
+}
+
+func (s *Server) del(key string) {
+	delete(s.strings, key)
+	delete(s.sets, key)
+	delete(s.hashes, key)
+	delete(s.zsets, key)
+	delete(s.expires, key)
+}
+
+// expire drops the key if its timeout has passed
+func (s *Server) expire(key string) {
+	if at, ok := s.expires[key]; ok && !time.Now().Before(at) {
+		s.del(key)
+	}
+}
+
+func (s *Server) keys(pattern string) []string {
+	re := regexp.MustCompile("^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern)) + "$")
+
+	all := make(map[string]bool)
+	for key := range s.strings {
+		all[key] = true
+	}
+	for key := range s.sets {
+		all[key] = true
+	}
+	for key := range s.hashes {
+		all[key] = true
+	}
+	for key := range s.zsets {
+		all[key] = true
+	}
+
+	var keys []string
+	for key := range all {
+		s.expire(key)
+		if s.exists(key) && re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func toStrings(args []interface{}) []string {
+	strs := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case []byte:
+			strs = append(strs, string(v))
+		default:
+			strs = append(strs, fmt.Sprint(v))
+		}
+	}
+
+	return strs
+}
+
+func byteReplies(strs []string) []interface{} {
+	reply := make([]interface{}, 0, len(strs))
+	for _, s := range strs {
+		reply = append(reply, []byte(s))
+	}
+
+	return reply
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
@@ -9,7 +9,13 @@ import (
 	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 )
 
-var RedisConn *redis.Pool
+// Pool hands out Redis connections. *redis.Pool satisfies it, and tests can
+// swap RedisConn for a fake to simulate an unreachable or in-memory Redis
+type Pool interface {
+	Get() redis.Conn
+}
+
+var RedisConn Pool
 
 // Setup Initialize the Redis instance
 func Setup() error {
@@ -62,6 +68,27 @@ func Set(key string, data interface{}, time int) error {
 	return nil
 }
 
+// SetNX set a key/value only if the key does not exist yet, reporting whether it was set
+func SetNX(key string, data interface{}, time int) (bool, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	value, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+
+	reply, err := redis.String(conn.Do("SET", key, value, "EX", time, "NX"))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return reply == "OK", nil
+}
+
 // Exists check a key
 func Exists(key string) bool {
 	conn := RedisConn.Get()
@@ -115,3 +142,141 @@ func LikeDeletes(key string) error {
 
 	return nil
 }
+
+// Keys get the keys matching a pattern
+func Keys(pattern string) ([]string, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	return redis.Strings(conn.Do("KEYS", pattern))
+}
+
+// Expire set a timeout on a key
+func Expire(key string, time int) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("EXPIRE", key, time)
+	return err
+}
+
+// SAdd add members to a set
+func SAdd(key string, members ...string) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SADD", redis.Args{}.Add(key).AddFlat(members)...)
+	return err
+}
+
+// SRem remove members from a set
+func SRem(key string, members ...string) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SREM", redis.Args{}.Add(key).AddFlat(members)...)
+	return err
+}
+
+// SMembers get all members of a set
+func SMembers(key string) ([]string, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	return redis.Strings(conn.Do("SMEMBERS", key))
+}
+
+// Incr increment the integer value of a key, returning the new value
+func Incr(key string) (int, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	return redis.Int(conn.Do("INCR", key))
+}
+
+// TTL get the remaining time to live of a key in seconds, negative if the key has no timeout or does not exist
+func TTL(key string) (int, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	return redis.Int(conn.Do("TTL", key))
+}
+
+// Rename rename a key, failing if it does not exist
+func Rename(key, newKey string) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("RENAME", key, newKey)
+	return err
+}
+
+// HIncrBy increment the integer value of a field of a hash
+func HIncrBy(key, field string, increment int) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("HINCRBY", key, field, increment)
+	return err
+}
+
+// HDel delete a field of a hash
+func HDel(key, field string) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("HDEL", key, field)
+	return err
+}
+
+// HGetAllInts get all fields of a hash holding integer values
+func HGetAllInts(key string) (map[string]int, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	return redis.IntMap(conn.Do("HGETALL", key))
+}
+
+// ZIncrBy increment the score of a member of a sorted set
+func ZIncrBy(key string, increment int, member string) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("ZINCRBY", key, increment, member)
+	return err
+}
+
+// ZAdd set the scores of members of a sorted set
+func ZAdd(key string, scores map[string]int) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	args := redis.Args{}.Add(key)
+	for member, score := range scores {
+		args = args.Add(score, member)
+	}
+	_, err := conn.Do("ZADD", args...)
+	return err
+}
+
+// ZUnionStore store the union of sorted sets in key, summing the scores
+func ZUnionStore(key string, keys ...string) error {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("ZUNIONSTORE", redis.Args{}.Add(key, len(keys)).AddFlat(keys)...)
+	return err
+}
+
+// ZRevRangeWithScores get the members of a sorted set from start to stop, highest score first,
+// alternating members and scores
+func ZRevRangeWithScores(key string, start, stop int) ([]string, error) {
+	conn := RedisConn.Get()
+	defer conn.Close()
+
+	return redis.Strings(conn.Do("ZREVRANGE", key, start, stop, "WITHSCORES"))
+}
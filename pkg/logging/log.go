@@ -17,7 +17,8 @@ var (
 	DefaultPrefix      = ""
 	DefaultCallerDepth = 2
 
-	logger     *log.Logger
+	// logger writes to stderr until Setup opens the log file
+	logger     = log.New(os.Stderr, "", log.LstdFlags)
 	logPrefix  = ""
 	levelFlags = []string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
 )
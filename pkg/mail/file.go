@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/file"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+)
+
+// FileMailer writes messages to .eml files instead of sending them, for local development
+type FileMailer struct {
+	Path string
+	From string
+}
+
+func (m *FileMailer) Send(msg *Message) error {
+	if err := file.IsNotExistMkDir(m.Path); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	if err := os.WriteFile(filepath.Join(m.Path, name), build(m.From, msg), 0600); err != nil {
+		return err
+	}
+
+	logging.Info("mail to", msg.To, "saved as", name)
+	return nil
+}
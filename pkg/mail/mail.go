@@ -0,0 +1,80 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+const (
+	DRIVER_SMTP = "smtp"
+	DRIVER_FILE = "file"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+var DefaultMailer Mailer
+
+// Setup Initialize the mailer selected by the Driver setting
+func Setup() {
+	switch setting.MailSetting.Driver {
+	case DRIVER_SMTP:
+		DefaultMailer = &SMTPMailer{
+			Host:     setting.MailSetting.Host,
+			Port:     setting.MailSetting.Port,
+			Username: setting.MailSetting.Username,
+			Password: setting.MailSetting.Password,
+			From:     setting.MailSetting.From,
+		}
+	default:
+		DefaultMailer = &FileMailer{
+			Path: GetMailFullPath(),
+			From: setting.MailSetting.From,
+		}
+	}
+}
+
+// Send delivers a message with the default mailer
+func Send(msg *Message) error {
+	return DefaultMailer.Send(msg)
+}
+
+// GetMailPath get the relative save path of the file mailer
+func GetMailPath() string {
+	return setting.MailSetting.SavePath
+}
+
+// GetMailFullPath get the full save path of the file mailer
+func GetMailFullPath() string {
+	return setting.AppSetting.RuntimeRootPath + GetMailPath()
+}
+
+// build renders a message in RFC 5322 format
+func build(from string, msg *Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.Replace(msg.Body, "\n", "\r\n", -1))
+
+	return buf.Bytes()
+}
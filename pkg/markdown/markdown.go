@@ -0,0 +1,758 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	TAB_STOP = 4
+	// CODE_INDENT is the indentation of a line of code
+	CODE_INDENT = 4
+	// MAX_NESTING bounds how deep block quotes and lists are nested
+	MAX_NESTING = 32
+	// MAX_LINK_TEXT and MAX_LINK_TARGET bound in bytes how far the "]" closing the text of a link
+	// and the ")" after its destination and title are looked for, so that rendering stays linear
+	MAX_LINK_TEXT   = 1000
+	MAX_LINK_TARGET = 4096
+
+	asciiPunct = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+var (
+	atxHeading  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematic    = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextH1    = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	setextH2    = regexp.MustCompile(`^ {0,3}-+[ \t]*$`)
+	fenceOpen   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	blockquote  = regexp.MustCompile(`^ {0,3}> ?`)
+	bulletItem  = regexp.MustCompile(`^( {0,3})([-*+])([ \t]+|$)`)
+	orderedItem = regexp.MustCompile(`^( {0,3})([0-9]{1,9})([.)])([ \t]+|$)`)
+	htmlBlock   = regexp.MustCompile(`^ {0,3}(?:<!--|</?[a-zA-Z][a-zA-Z0-9-]*(?:[\s/>]|$))`)
+	tableDelim  = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	autolink    = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailAuto   = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9.-]*[a-zA-Z0-9])?)>`)
+	inlineHTML  = regexp.MustCompile(`^(?:<!--[\s\S]*?-->|</?[a-zA-Z][a-zA-Z0-9-]*(?:\s+[a-zA-Z_:][a-zA-Z0-9_.:-]*(?:\s*=\s*(?:[^\s"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*\s*/?>)`)
+	entity      = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+// ToHTML renders markdown to HTML: headings, paragraphs, emphasis, links, images, code,
+// block quotes, lists, tables and thematic breaks, as in CommonMark and GitHub tables.
+// Raw HTML in the source is passed through, the result must be checked by sanitize.HTML.
+func ToHTML(src string) string {
+	src = strings.Replace(src, "\r\n", "\n", -1)
+	src = strings.Replace(src, "\r", "\n", -1)
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+
+	var b strings.Builder
+	renderBlocks(&b, lines, false, 0)
+	return b.String()
+}
+
+// expandTabs replaces the tabs of the indentation of a line with spaces
+func expandTabs(line string) string {
+	var b strings.Builder
+	col := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			b.WriteByte(' ')
+			col++
+		case '\t':
+			n := TAB_STOP - col%TAB_STOP
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		default:
+			b.WriteString(line[i:])
+			return b.String()
+		}
+	}
+
+	return b.String()
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// renderBlocks renders a sequence of blocks. In a tight list paragraphs are not wrapped in <p>.
+func renderBlocks(b *strings.Builder, lines []string, tight bool, depth int) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case fenceOpen.MatchString(line):
+			i = renderFence(b, lines, i)
+		case indentOf(line) >= CODE_INDENT:
+			i = renderIndentedCode(b, lines, i)
+		case atxHeading.MatchString(line):
+			m := atxHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + inline(strings.TrimSpace(m[2])) + "</h" + level + ">\n")
+			i++
+		case thematic.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case blockquote.MatchString(line) && depth < MAX_NESTING:
+			i = renderBlockquote(b, lines, i, depth)
+		case isListItem(line) && depth < MAX_NESTING:
+			i = renderList(b, lines, i, depth)
+		case htmlBlock.MatchString(line):
+			for ; i < len(lines) && !isBlank(lines[i]); i++ {
+				b.WriteString(lines[i] + "\n")
+			}
+		case isTableStart(lines, i):
+			i = renderTable(b, lines, i)
+		default:
+			i = renderParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	m := fenceOpen.FindStringSubmatch(lines[i])
+	indent, fence, lang := len(m[1]), m[2], m[3]
+
+	b.WriteString("<pre><code")
+	if lang != "" {
+		b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+	}
+	b.WriteString(">")
+
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if indentOf(line) < CODE_INDENT && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		// Remove up to the indentation of the opening fence
+		n := indentOf(line)
+		if n > indent {
+			n = indent
+		}
+		b.WriteString(html.EscapeString(line[n:]) + "\n")
+	}
+
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func renderIndentedCode(b *strings.Builder, lines []string, i int) int {
+	var code []string
+	for ; i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= CODE_INDENT); i++ {
+		if isBlank(lines[i]) {
+			code = append(code, "")
+		} else {
+			code = append(code, lines[i][CODE_INDENT:])
+		}
+	}
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+
+	b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "\n</code></pre>\n")
+	return i
+}
+
+func renderBlockquote(b *strings.Builder, lines []string, i, depth int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := blockquote.FindStringIndex(line); loc != nil {
+			inner = append(inner, line[loc[1]:])
+			continue
+		}
+		// A paragraph in the quote continues on lines without ">"
+		if isBlank(line) || len(inner) == 0 || isBlank(inner[len(inner)-1]) || startsBlock(line) {
+			break
+		}
+		inner = append(inner, line)
+	}
+
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, inner, false, depth+1)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+type listMarker struct {
+	ordered bool
+	start   int
+	// delim is the bullet or the character after the number
+	delim string
+	// content is the indentation of the content of the item
+	content int
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	if m := bulletItem.FindStringSubmatch(line); m != nil {
+		return listMarker{delim: m[2], content: markerContent(line, len(m[1])+1, m[3])}, true
+	}
+	if m := orderedItem.FindStringSubmatch(line); m != nil {
+		start, _ := strconv.Atoi(m[2])
+		return listMarker{ordered: true, start: start, delim: m[3], content: markerContent(line, len(m[1])+len(m[2])+1, m[4])}, true
+	}
+
+	return listMarker{}, false
+}
+
+// markerContent is where the content of an item starts: after the marker and its spaces,
+// or a single space after the marker if it is followed by more, which start indented code
+func markerContent(line string, marker int, spaces string) int {
+	if strings.TrimSpace(line[marker:]) == "" || len(spaces) > CODE_INDENT {
+		return marker + 1
+	}
+
+	return marker + len(spaces)
+}
+
+func isListItem(line string) bool {
+	_, ok := parseListMarker(line)
+	return ok && !thematic.MatchString(line)
+}
+
+func renderList(b *strings.Builder, lines []string, i, depth int) int {
+	first, _ := parseListMarker(lines[i])
+	var items [][]string
+	loose := false
+	blank := false
+
+	for i < len(lines) {
+		line := lines[i]
+		if marker, ok := parseListMarker(line); ok && !thematic.MatchString(line) && indentOf(line) < first.content {
+			if marker.ordered != first.ordered || marker.delim != first.delim {
+				break
+			}
+			loose = loose || (blank && len(items) > 0)
+			blank = false
+			content := ""
+			if marker.content < len(line) {
+				content = line[marker.content:]
+			}
+			// The following lines of the item are indented like its first line
+			first.content = marker.content
+			items = append(items, []string{content})
+			i++
+			continue
+		}
+
+		item := &items[len(items)-1]
+		switch {
+		case isBlank(line):
+			blank = true
+			*item = append(*item, "")
+		case indentOf(line) >= first.content:
+			if blank {
+				loose = loose || hasContent(*item)
+			}
+			blank = false
+			*item = append(*item, line[first.content:])
+		case !blank && !startsBlock(line):
+			// A paragraph in the item continues on unindented lines
+			*item = append(*item, line)
+		default:
+			return finishList(b, items, first, loose, depth, i)
+		}
+		i++
+	}
+
+	return finishList(b, items, first, loose, depth, i)
+}
+
+func hasContent(lines []string) bool {
+	for _, line := range lines {
+		if !isBlank(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func finishList(b *strings.Builder, items [][]string, marker listMarker, loose bool, depth, i int) int {
+	tag := "ul"
+	if marker.ordered {
+		tag = "ol"
+	}
+
+	b.WriteString("<" + tag)
+	if marker.ordered && marker.start != 1 {
+		b.WriteString(` start="` + strconv.Itoa(marker.start) + `"`)
+	}
+	b.WriteString(">\n")
+	for _, item := range items {
+		b.WriteString("<li>")
+		renderBlocks(b, item, !loose, depth+1)
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+
+	return i
+}
+
+func renderTable(b *strings.Builder, lines []string, i int) int {
+	header := splitRow(lines[i])
+	var aligns []string
+	for _, cell := range splitRow(lines[i+1]) {
+		cell = strings.TrimSpace(cell)
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns = append(aligns, "center")
+		case left:
+			aligns = append(aligns, "left")
+		case right:
+			aligns = append(aligns, "right")
+		default:
+			aligns = append(aligns, "")
+		}
+	}
+
+	writeRow := func(cells []string, tag string) {
+		b.WriteString("<tr>\n")
+		for n := range header {
+			b.WriteString("<" + tag)
+			if n < len(aligns) && aligns[n] != "" {
+				b.WriteString(` align="` + aligns[n] + `"`)
+			}
+			b.WriteString(">")
+			if n < len(cells) {
+				b.WriteString(inline(strings.TrimSpace(cells[n])))
+			}
+			b.WriteString("</" + tag + ">\n")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("<table>\n<thead>\n")
+	writeRow(header, "th")
+	b.WriteString("</thead>\n")
+
+	i += 2
+	if i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|") {
+		b.WriteString("<tbody>\n")
+		for ; i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|"); i++ {
+			writeRow(splitRow(lines[i]), "td")
+		}
+		b.WriteString("</tbody>\n")
+	}
+	b.WriteString("</table>\n")
+
+	return i
+}
+
+// splitRow splits a table row on the pipes that are not escaped
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if line[i] == '|' {
+			cells = append(cells, line[start:i])
+			start = i + 1
+		}
+	}
+	cells = append(cells, line[start:])
+
+	for i, cell := range cells {
+		cells[i] = strings.Replace(cell, `\|`, "|", -1)
+	}
+
+	return cells
+}
+
+func renderParagraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	var text []string
+	for ; i < len(lines) && !isBlank(lines[i]); i++ {
+		line := lines[i]
+		if len(text) > 0 {
+			if setextH1.MatchString(line) || setextH2.MatchString(line) {
+				level := "1"
+				if setextH2.MatchString(line) {
+					level = "2"
+				}
+				b.WriteString("<h" + level + ">" + inline(strings.TrimSpace(strings.Join(text, "\n"))) + "</h" + level + ">\n")
+				return i + 1
+			}
+			if startsBlock(line) {
+				break
+			}
+		}
+		text = append(text, strings.TrimLeft(line, " "))
+	}
+
+	content := inline(strings.TrimRight(strings.Join(text, "\n"), " "))
+	if tight {
+		b.WriteString(content)
+	} else {
+		b.WriteString("<p>" + content + "</p>\n")
+	}
+
+	return i
+}
+
+// startsBlock reports whether a line interrupts a paragraph. A list only does if its first item
+// has content and, when ordered, starts at 1, so that "2019. was a good year" stays text.
+func startsBlock(line string) bool {
+	if fenceOpen.MatchString(line) || atxHeading.MatchString(line) || thematic.MatchString(line) ||
+		blockquote.MatchString(line) || htmlBlock.MatchString(line) {
+		return true
+	}
+
+	marker, ok := parseListMarker(line)
+	return ok && marker.content < len(line) && !isBlank(line[marker.content:]) && (!marker.ordered || marker.start == 1)
+}
+
+// isTableStart reports whether a header row with as many cells as the delimiter row below starts at i
+func isTableStart(lines []string, i int) bool {
+	return i+1 < len(lines) && strings.Contains(lines[i], "|") && tableDelim.MatchString(lines[i+1]) &&
+		len(splitRow(lines[i])) == len(splitRow(lines[i+1]))
+}
+
+// inline renders the emphasis, links, images, code spans, line breaks and escapes of a text
+func inline(s string) string {
+	var b strings.Builder
+	// unclosed holds the delimiters without a closer in the rest of the text, later openers need not look again
+	unclosed := make(map[string]bool)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte(asciiPunct, s[i+1]) >= 0 {
+				b.WriteString(html.EscapeString(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\n' {
+				b.WriteString("<br>\n")
+				i += 2
+				continue
+			}
+		case '`':
+			if n := renderCodeSpan(&b, s, i); n > 0 {
+				i = n
+				continue
+			}
+			run := backtickRun(s, i)
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' {
+				if n := renderLink(&b, s, i+1, true); n > 0 {
+					i = n
+					continue
+				}
+			}
+		case '[':
+			if n := renderLink(&b, s, i, false); n > 0 {
+				i = n
+				continue
+			}
+		case '<':
+			if m := autolink.FindStringSubmatch(s[i:]); m != nil {
+				b.WriteString(`<a href="` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+			if m := emailAuto.FindStringSubmatch(s[i:]); m != nil {
+				b.WriteString(`<a href="mailto:` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+			if m := inlineHTML.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+		case '&':
+			if m := entity.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+		case '*', '_', '~':
+			if n := renderEmphasis(&b, s, i, unclosed); n > 0 {
+				i = n
+				continue
+			}
+			// An unmatched run stays as it is
+			j := i
+			for j < len(s) && s[j] == c {
+				j++
+			}
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		case ' ':
+			j := i
+			for j < len(s) && s[j] == ' ' {
+				j++
+			}
+			// Two spaces at the end of a line are a hard line break
+			if j < len(s) && s[j] == '\n' {
+				if j-i >= 2 {
+					b.WriteString("<br>")
+				}
+				i = j
+				continue
+			}
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+
+	return b.String()
+}
+
+func backtickRun(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+
+	return n
+}
+
+// renderCodeSpan renders the code span starting at i and returns where it ends, 0 if there is none
+func renderCodeSpan(b *strings.Builder, s string, i int) int {
+	run := backtickRun(s, i)
+	for j := i + run; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		closing := backtickRun(s, j)
+		if closing == run {
+			code := strings.Replace(s[i+run:j], "\n", " ", -1)
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			return j + closing
+		}
+		j += closing
+	}
+
+	return 0
+}
+
+// renderLink renders the link or image whose text starts with the "[" at i and returns where it ends,
+// 0 if there is none
+func renderLink(b *strings.Builder, s string, i int, image bool) int {
+	// Find the matching "]"
+	depth := 0
+	end := -1
+	for j := i; j < len(s) && j < i+MAX_LINK_TEXT && end == -1; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				end = j
+			}
+		}
+	}
+	if end == -1 || end+1 >= len(s) || s[end+1] != '(' {
+		return 0
+	}
+
+	dest, title, n := parseDestination(s[:min(len(s), end+2+MAX_LINK_TARGET)], end+2)
+	if n == 0 {
+		return 0
+	}
+
+	text := s[i+1 : end]
+	if image {
+		b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(text) + `"`)
+		if title != "" {
+			b.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		b.WriteString(">")
+		return n
+	}
+
+	b.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	b.WriteString(">" + inline(text) + "</a>")
+	return n
+}
+
+// parseDestination parses `url "title")` from i and returns where it ends, 0 if it is malformed
+func parseDestination(s string, i int) (string, string, int) {
+	skipSpaces := func() {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\n') {
+			i++
+		}
+	}
+
+	skipSpaces()
+	var dest string
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexAny(s[i+1:], ">\n")
+		if end == -1 || s[i+1+end] != '>' {
+			return "", "", 0
+		}
+		dest = s[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		start, parens := i, 0
+		for ; i < len(s) && s[i] > ' '; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			} else if s[i] == '(' {
+				parens++
+			} else if s[i] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:i]
+	}
+
+	skipSpaces()
+	var title string
+	if i < len(s) && (s[i] == '"' || s[i] == '\'' || s[i] == '(') {
+		closing := s[i]
+		if closing == '(' {
+			closing = ')'
+		}
+		end := strings.IndexByte(s[i+1:], closing)
+		if end == -1 {
+			return "", "", 0
+		}
+		title = s[i+1 : i+1+end]
+		i += end + 2
+		skipSpaces()
+	}
+
+	if i >= len(s) || s[i] != ')' {
+		return "", "", 0
+	}
+
+	return unescape(dest), unescape(title), i + 1
+}
+
+// unescape removes the backslashes of escaped punctuation and decodes entities
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(asciiPunct, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return html.UnescapeString(b.String())
+}
+
+// renderEmphasis renders the emphasis (*, _), strong emphasis (**, __) or strikethrough (~~)
+// opened by the delimiters at i and returns where it ends, 0 if it is not closed. Delimiters
+// found unclosed are added to unclosed.
+func renderEmphasis(b *strings.Builder, s string, i int, unclosed map[string]bool) int {
+	c := s[i]
+	run := 0
+	for i+run < len(s) && s[i+run] == c {
+		run++
+	}
+
+	// Intraword underscores are not emphasis
+	if c == '_' && i > 0 && isAlnum(s[i-1]) {
+		return 0
+	}
+
+	type kind struct {
+		delim string
+		tag   string
+	}
+	kinds := []kind{{string([]byte{c, c}), "strong"}, {string([]byte{c}), "em"}}
+	if c == '~' {
+		kinds = []kind{{"~~", "del"}}
+	}
+
+	for _, k := range kinds {
+		if run < len(k.delim) || unclosed[k.delim] {
+			continue
+		}
+		from := i + len(k.delim)
+		if from >= len(s) || s[from] == ' ' || s[from] == '\n' {
+			continue
+		}
+		end := findCloser(s, from, k.delim)
+		if end == -1 {
+			unclosed[k.delim] = true
+			continue
+		}
+
+		b.WriteString("<" + k.tag + ">" + inline(s[from:end]) + "</" + k.tag + ">")
+		return end + len(k.delim)
+	}
+
+	return 0
+}
+
+// findCloser finds the delimiters closing emphasis opened before from, skipping code spans and escapes
+func findCloser(s string, from int, delim string) int {
+	c := delim[0]
+	for j := from; j+len(delim) <= len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '`':
+			run := backtickRun(s, j)
+			if end := strings.Index(s[j+run:], s[j:j+run]); end != -1 {
+				j += run + end
+			}
+			j += run - 1
+		case strings.HasPrefix(s[j:], delim):
+			run := 0
+			for j+run < len(s) && s[j+run] == c {
+				run++
+			}
+			// A single delimiter does not close inside a longer run, which is strong emphasis of its own
+			if len(delim) == 1 && run > 1 {
+				j += run - 1
+				continue
+			}
+			after := j + len(delim)
+			if j > from && s[j-1] != ' ' && s[j-1] != '\n' && (c != '_' || after >= len(s) || !isAlnum(s[after])) {
+				return j
+			}
+			j += run - 1
+		}
+	}
+
+	return -1
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,70 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"headings", "# Title #\n\nSetext\n===\n\nSub\n---", "<h1>Title</h1>\n<h1>Setext</h1>\n<h2>Sub</h2>\n"},
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one\ntwo</p>\n<p>three</p>\n"},
+		{"emphasis", "*em* **strong** ~~del~~ _u_ snake_case_name", "<p><em>em</em> <strong>strong</strong> <del>del</del> <em>u</em> snake_case_name</p>\n"},
+		{"nested emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>\n"},
+		{"unclosed emphasis", "a * b ** c ~~ d", "<p>a * b ** c ~~ d</p>\n"},
+		{"escapes", `\*not em\* \[not link\] a<b & c>d`, "<p>*not em* [not link] a&lt;b &amp; c&gt;d</p>\n"},
+		{"code spans", "a `code <b>` and ``a ` b`` end", "<p>a <code>code &lt;b&gt;</code> and <code>a ` b</code> end</p>\n"},
+		{"links", `[link](https://example.com "Title") [rel](</a b>) [*x*](/x)`, `<p><a href="https://example.com" title="Title">link</a> <a href="/a b">rel</a> <a href="/x"><em>x</em></a></p>` + "\n"},
+		{"nested brackets", "[text [nested]](/x)", "<p><a href=\"/x\">text [nested]</a></p>\n"},
+		{"not links", "[no dest] [a](b", "<p>[no dest] [a](b</p>\n"},
+		{"link text too long", "[" + strings.Repeat("a", MAX_LINK_TEXT) + "](/x)", "<p>[" + strings.Repeat("a", MAX_LINK_TEXT) + "](/x)</p>\n"},
+		{"images", `![alt "x"](/a.png 'T')`, `<p><img src="/a.png" alt="alt &#34;x&#34;" title="T"></p>` + "\n"},
+		{"link destination escaping", `[x](/a"b&c)`, `<p><a href="/a&#34;b&amp;c">x</a></p>` + "\n"},
+		{"autolinks", "<https://example.com/?a=1&b=2> <me@example.com>", `<p><a href="https://example.com/?a=1&amp;b=2">https://example.com/?a=1&amp;b=2</a> <a href="mailto:me@example.com">me@example.com</a></p>` + "\n"},
+		{"line breaks", "one  \ntwo\\\nthree", "<p>one<br>\ntwo<br>\nthree</p>\n"},
+		{"block quotes", "> quote\n> > nested\n\ntext", "<blockquote>\n<p>quote</p>\n<blockquote>\n<p>nested</p>\n</blockquote>\n</blockquote>\n<p>text</p>\n"},
+		{"lists", "- a\n- b\n  - c\n\n3. three\n4. four", "<ul>\n<li>a</li>\n<li>b<ul>\n<li>c</li>\n</ul>\n</li>\n</ul>\n<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n"},
+		{"loose lists", "- loose\n\n- items", "<ul>\n<li><p>loose</p>\n</li>\n<li><p>items</p>\n</li>\n</ul>\n"},
+		{"numbers are not lists in paragraphs", "In\n2019. was a good year", "<p>In\n2019. was a good year</p>\n"},
+		{"fenced code", "```go\nfunc main() {}\n<x>\n```", "<pre><code class=\"language-go\">func main() {}\n&lt;x&gt;\n</code></pre>\n"},
+		{"indented code", "    a <b>", "<pre><code>a &lt;b&gt;\n</code></pre>\n"},
+		{"tables", "| a | b |\n|:--|--:|\n| 1 | 2 |", "<table>\n<thead>\n<tr>\n<th align=\"left\">a</th>\n<th align=\"right\">b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td align=\"left\">1</td>\n<td align=\"right\">2</td>\n</tr>\n</tbody>\n</table>\n"},
+		{"thematic breaks", "***\n\n___", "<hr>\n<hr>\n"},
+		// Raw HTML is passed through for sanitize.HTML to check
+		{"html blocks", "<div class=\"x\">\n*raw*\n</div>", "<div class=\"x\">\n*raw*\n</div>\n"},
+		{"inline html", "a <b onclick=\"x\">b</b> <!-- c -->", "<p>a <b onclick=\"x\">b</b> <!-- c --></p>\n"},
+		{"entities", "&copy; &#169; & x", "<p>&copy; &#169; &amp; x</p>\n"},
+		{"windows line endings", "a\r\n\r\nb", "<p>a</p>\n<p>b</p>\n"},
+	}
+	for _, tt := range tests {
+		if got := ToHTML(tt.src); got != tt.want {
+			t.Errorf("%s: ToHTML(%q) = %q, want %q", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+// TestLinearTime guards against inputs that used to take seconds, each must take well under one
+func TestLinearTime(t *testing.T) {
+	tests := map[string]string{
+		"unclosed images":        strings.Repeat("![", 20000),
+		"unclosed destinations":  strings.Repeat("[a](", 20000),
+		"unclosed titles":        strings.Repeat(`[a](b "`, 10000),
+		"nested brackets":        strings.Repeat("[", 10000) + strings.Repeat("]", 10000),
+		"unclosed emphasis":      strings.Repeat("*a ", 20000),
+		"unclosed strong":        strings.Repeat("**a ", 20000),
+		"unclosed underscores":   strings.Repeat("_a ", 20000),
+		"unclosed strikethrough": strings.Repeat("~~a ", 20000),
+		"nested emphasis":        strings.Repeat("*a ", 5000) + strings.Repeat("a* ", 5000),
+		"unclosed code spans":    strings.Repeat("`a ``", 20000),
+	}
+	for name, src := range tests {
+		start := time.Now()
+		ToHTML(src)
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("%s: took %v", name, d)
+		}
+	}
+}
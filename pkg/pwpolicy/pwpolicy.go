@@ -0,0 +1,130 @@
+package pwpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+var (
+	ErrTooShort = errors.New("password is too short")
+	ErrTooWeak  = errors.New("password does not mix enough character classes")
+	ErrBreached = errors.New("password appears in a list of breached passwords")
+	ErrReused   = errors.New("password was used recently")
+)
+
+var codes = map[error]int{
+	ErrTooShort: e.ERROR_PASSWORD_TOO_SHORT,
+	ErrTooWeak:  e.ERROR_PASSWORD_TOO_WEAK,
+	ErrBreached: e.ERROR_PASSWORD_BREACHED,
+	ErrReused:   e.ERROR_PASSWORD_REUSED,
+}
+
+// breached holds the upper case SHA-1 hex of every password in the breach list
+var breached map[string]struct{}
+
+// Setup Initialize the password policy, loading the breach list if one is configured
+func Setup() {
+	path := setting.AppSetting.PasswordBreachListFile
+	if path == "" {
+		return
+	}
+
+	var err error
+	if breached, err = loadBreachList(path); err != nil {
+		log.Fatalf("pwpolicy.Setup, fail to load the breach list: %v", err)
+	}
+}
+
+// Validate checks a new password against the length, character class and breach list rules.
+// Reuse of earlier passwords needs the account's history and is checked by the caller.
+func Validate(password string) error {
+	if len([]rune(password)) < setting.AppSetting.PasswordMinLength {
+		return ErrTooShort
+	}
+	if countClasses(password) < setting.AppSetting.PasswordMinCharClasses {
+		return ErrTooWeak
+	}
+	if _, ok := breached[hashSHA1(password)]; ok {
+		return ErrBreached
+	}
+
+	return nil
+}
+
+// GetErrorCode maps a policy violation to its response code
+func GetErrorCode(err error) (int, bool) {
+	code, ok := codes[err]
+	return code, ok
+}
+
+// countClasses counts how many of lower case letters, upper case letters, digits
+// and other characters a password contains
+func countClasses(password string) int {
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	return lower + upper + digit + other
+}
+
+// loadBreachList reads one password per line, either in plain text or as SHA-1 hex.
+// Lines in the "HASH:count" format of Have I Been Pwned downloads are accepted as well.
+func loadBreachList(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if hash := strings.SplitN(line, ":", 2)[0]; isSHA1Hex(hash) {
+			list[strings.ToUpper(hash)] = struct{}{}
+		} else {
+			list[hashSHA1(line)] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != sha1.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func hashSHA1(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,137 @@
+package pwpolicy
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+func setup(t *testing.T, minLength, minClasses int, breachList string) {
+	t.Helper()
+
+	old, oldBreached := *setting.AppSetting, breached
+	t.Cleanup(func() {
+		*setting.AppSetting = old
+		breached = oldBreached
+	})
+
+	setting.AppSetting.PasswordMinLength = minLength
+	setting.AppSetting.PasswordMinCharClasses = minClasses
+	setting.AppSetting.PasswordBreachListFile = ""
+	breached = nil
+	if breachList != "" {
+		path := filepath.Join(t.TempDir(), "breached.txt")
+		if err := ioutil.WriteFile(path, []byte(breachList), 0644); err != nil {
+			t.Fatal(err)
+		}
+		setting.AppSetting.PasswordBreachListFile = path
+	}
+	Setup()
+}
+
+func TestValidate(t *testing.T) {
+	setup(t, 8, 2, "Letmein123\n")
+
+	tests := []struct {
+		password string
+		want     error
+	}{
+		{"", ErrTooShort},
+		{"Ab1!", ErrTooShort},
+		{"Ab1!xyz", ErrTooShort},
+		// Length counts characters, not bytes
+		{"äöüäöüä", ErrTooShort},
+		{"äöüäöüäÄ", nil},
+		{"abcdefgh", ErrTooWeak},
+		{"ABCDEFGH", ErrTooWeak},
+		{"12345678", ErrTooWeak},
+		{"!@#$%^&*", ErrTooWeak},
+		{"abcdefg1", nil},
+		{"abcdEFGH", nil},
+		{"Letmein123", ErrBreached},
+		// Length and classes are checked before the breach list
+		{"Letmein", ErrTooShort},
+	}
+	for _, tt := range tests {
+		if got := Validate(tt.password); got != tt.want {
+			t.Errorf("Validate(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestValidateBreachList(t *testing.T) {
+	// "password" as SHA-1 in the Have I Been Pwned format, "Letmein123" as lower case hex
+	// and "Summer2024!" in plain text with a Windows line ending
+	setup(t, 0, 0, "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3730471\n"+
+		strings.ToLower(hashSHA1("Letmein123"))+"\n\n"+
+		"Summer2024!\r\n")
+
+	tests := map[string]error{
+		"password":    ErrBreached,
+		"Letmein123":  ErrBreached,
+		"Summer2024!": ErrBreached,
+		// The list is matched exactly
+		"Password":    nil,
+		"summer2024!": nil,
+		"Summer2024":  nil,
+		"":            nil,
+	}
+	for password, want := range tests {
+		if got := Validate(password); got != want {
+			t.Errorf("Validate(%q) = %v, want %v", password, got, want)
+		}
+	}
+}
+
+func TestValidateCharClasses(t *testing.T) {
+	tests := []struct {
+		minClasses int
+		password   string
+		want       error
+	}{
+		{0, "aaaaaaaa", nil},
+		{1, "aaaaaaaa", nil},
+		{3, "aaaaAAAA", ErrTooWeak},
+		{3, "aaaaAAA1", nil},
+		{4, "aaaaAA11", ErrTooWeak},
+		{4, "aaaAA11!", nil},
+		// Letters without case and spaces count as other characters
+		{4, "aA1 中文字符", nil},
+	}
+	for _, tt := range tests {
+		setup(t, 8, tt.minClasses, "")
+		if got := Validate(tt.password); got != tt.want {
+			t.Errorf("Validate(%q) with %d classes = %v, want %v", tt.password, tt.minClasses, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWithoutBreachList(t *testing.T) {
+	setup(t, 8, 2, "")
+	if err := Validate("Password1"); err != nil {
+		t.Errorf("Validate() without a breach list = %v, want nil", err)
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	tests := map[error]int{
+		ErrTooShort: e.ERROR_PASSWORD_TOO_SHORT,
+		ErrTooWeak:  e.ERROR_PASSWORD_TOO_WEAK,
+		ErrBreached: e.ERROR_PASSWORD_BREACHED,
+		ErrReused:   e.ERROR_PASSWORD_REUSED,
+	}
+	for err, want := range tests {
+		if got, ok := GetErrorCode(err); !ok || got != want {
+			t.Errorf("GetErrorCode(%v) = %d, %v, want %d, true", err, got, ok, want)
+		}
+	}
+
+	if _, ok := GetErrorCode(io.ErrUnexpectedEOF); ok {
+		t.Error("GetErrorCode() of another error = true, want false")
+	}
+}
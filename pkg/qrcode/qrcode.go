@@ -1,7 +1,10 @@
 package qrcode
 
 import (
+	"bytes"
+	"encoding/base64"
 	"image/jpeg"
+	"image/png"
 
 	"github.com/boombuler/barcode"
 	"github.com/boombuler/barcode/qr"
@@ -90,3 +93,24 @@ func (q *QrCode) Encode(path string) (string, string, error) {
 
 	return name, path, nil
 }
+
+// EncodeDataURI generate QR code as a PNG data URI without writing it to disk,
+// for codes that must not be served from the public qrcode directory
+func (q *QrCode) EncodeDataURI() (string, error) {
+	code, err := qr.Encode(q.URL, q.Level, q.Mode)
+	if err != nil {
+		return "", err
+	}
+
+	code, err = barcode.Scale(code, q.Width, q.Height)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return "", err
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,88 @@
+package rbac
+
+const (
+	ROLE_ADMIN  = "admin"
+	ROLE_EDITOR = "editor"
+	ROLE_AUTHOR = "author"
+	ROLE_READER = "reader"
+)
+
+const (
+	PERM_TAGS_READ   = "tags:read"
+	PERM_TAGS_WRITE  = "tags:write"
+	PERM_TAGS_DELETE = "tags:delete"
+
+	PERM_ARTICLES_READ   = "articles:read"
+	PERM_ARTICLES_WRITE  = "articles:write"
+	PERM_ARTICLES_DELETE = "articles:delete"
+	// PERM_ARTICLES_MANAGE allows editing and deleting articles of other users
+	PERM_ARTICLES_MANAGE = "articles:manage"
+	// PERM_ARTICLES_PUBLISH allows publishing, scheduling, unpublishing and archiving articles
+	PERM_ARTICLES_PUBLISH = "articles:publish"
+
+	PERM_COMMENTS_WRITE = "comments:write"
+	// PERM_COMMENTS_MODERATE allows approving, rejecting and deleting comments of other users
+	PERM_COMMENTS_MODERATE = "comments:moderate"
+
+	PERM_USERS_READ   = "users:read"
+	PERM_USERS_WRITE  = "users:write"
+	PERM_USERS_DELETE = "users:delete"
+
+	PERM_OAUTH_CLIENTS_MANAGE = "oauth_clients:manage"
+
+	PERM_AUDIT_READ = "audit:read"
+)
+
+var rolePermissions = map[string][]string{
+	ROLE_ADMIN: {
+		PERM_TAGS_READ, PERM_TAGS_WRITE, PERM_TAGS_DELETE,
+		PERM_ARTICLES_READ, PERM_ARTICLES_WRITE, PERM_ARTICLES_DELETE, PERM_ARTICLES_MANAGE, PERM_ARTICLES_PUBLISH,
+		PERM_COMMENTS_WRITE, PERM_COMMENTS_MODERATE,
+		PERM_USERS_READ, PERM_USERS_WRITE, PERM_USERS_DELETE,
+		PERM_OAUTH_CLIENTS_MANAGE,
+		PERM_AUDIT_READ,
+	},
+	ROLE_EDITOR: {
+		PERM_TAGS_READ, PERM_TAGS_WRITE, PERM_TAGS_DELETE,
+		PERM_ARTICLES_READ, PERM_ARTICLES_WRITE, PERM_ARTICLES_DELETE, PERM_ARTICLES_MANAGE, PERM_ARTICLES_PUBLISH,
+		PERM_COMMENTS_WRITE, PERM_COMMENTS_MODERATE,
+	},
+	ROLE_AUTHOR: {
+		PERM_TAGS_READ,
+		PERM_ARTICLES_READ, PERM_ARTICLES_WRITE, PERM_ARTICLES_DELETE,
+		PERM_COMMENTS_WRITE,
+	},
+	ROLE_READER: {
+		PERM_TAGS_READ,
+		PERM_ARTICLES_READ,
+		PERM_COMMENTS_WRITE,
+	},
+}
+
+// IsValidRole checks whether a role is known
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsValidPermission checks whether any role grants a permission
+func IsValidPermission(permission string) bool {
+	for role := range rolePermissions {
+		if HasPermission(role, permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasPermission checks whether a role grants a permission
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package rbac
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	all := []string{
+		PERM_TAGS_READ, PERM_TAGS_WRITE, PERM_TAGS_DELETE,
+		PERM_ARTICLES_READ, PERM_ARTICLES_WRITE, PERM_ARTICLES_DELETE, PERM_ARTICLES_MANAGE, PERM_ARTICLES_PUBLISH,
+		PERM_COMMENTS_WRITE, PERM_COMMENTS_MODERATE,
+		PERM_USERS_READ, PERM_USERS_WRITE, PERM_USERS_DELETE,
+		PERM_OAUTH_CLIENTS_MANAGE,
+		PERM_AUDIT_READ,
+	}
+	granted := map[string][]string{
+		ROLE_ADMIN: all,
+		ROLE_EDITOR: {
+			PERM_TAGS_READ, PERM_TAGS_WRITE, PERM_TAGS_DELETE,
+			PERM_ARTICLES_READ, PERM_ARTICLES_WRITE, PERM_ARTICLES_DELETE, PERM_ARTICLES_MANAGE, PERM_ARTICLES_PUBLISH,
+			PERM_COMMENTS_WRITE, PERM_COMMENTS_MODERATE,
+		},
+		ROLE_AUTHOR: {
+			PERM_TAGS_READ,
+			PERM_ARTICLES_READ, PERM_ARTICLES_WRITE, PERM_ARTICLES_DELETE,
+			PERM_COMMENTS_WRITE,
+		},
+		ROLE_READER: {
+			PERM_TAGS_READ,
+			PERM_ARTICLES_READ,
+			PERM_COMMENTS_WRITE,
+		},
+		// Unknown roles, such as the empty role of a token without one, get nothing
+		"":      nil,
+		"guest": nil,
+	}
+
+	for role, perms := range granted {
+		want := make(map[string]bool, len(perms))
+		for _, p := range perms {
+			want[p] = true
+		}
+		for _, p := range all {
+			if got := HasPermission(role, p); got != want[p] {
+				t.Errorf("HasPermission(%q, %q) = %v, want %v", role, p, got, want[p])
+			}
+		}
+	}
+}
+
+func TestIsValidRole(t *testing.T) {
+	tests := map[string]bool{
+		ROLE_ADMIN:  true,
+		ROLE_EDITOR: true,
+		ROLE_AUTHOR: true,
+		ROLE_READER: true,
+		"":          false,
+		"Admin":     false,
+		"root":      false,
+	}
+	for role, want := range tests {
+		if got := IsValidRole(role); got != want {
+			t.Errorf("IsValidRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestIsValidPermission(t *testing.T) {
+	tests := map[string]bool{
+		PERM_TAGS_READ:       true,
+		PERM_AUDIT_READ:      true,
+		PERM_ARTICLES_MANAGE: true,
+		"":                   false,
+		"tags:*":             false,
+		"articles:purge":     false,
+	}
+	for permission, want := range tests {
+		if got := IsValidPermission(permission); got != want {
+			t.Errorf("IsValidPermission(%q) = %v, want %v", permission, got, want)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package sanitize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// UnsafeError describes the first unsafe part of a document
+type UnsafeError struct {
+	Reason string
+}
+
+func (e *UnsafeError) Error() string {
+	return "unsafe HTML: " + e.Reason
+}
+
+// Heading is an entry of the table of contents of a document
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// allowedTags lists the elements a document may use and the attributes of each, besides globalAttrs
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "div": {"class"}, "span": {"class"},
+	"h1": {"id"}, "h2": {"id"}, "h3": {"id"}, "h4": {"id"}, "h5": {"id"}, "h6": {"id"},
+	"blockquote": {"cite"}, "pre": {"class"}, "code": {"class"},
+	"em": nil, "strong": nil, "b": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+	"sub": nil, "sup": nil, "mark": nil, "small": nil, "abbr": nil, "kbd": nil, "q": {"cite"}, "cite": nil,
+	"a":   {"href", "name", "rel"},
+	"img": {"src", "alt", "width", "height"},
+	"ul":  nil, "ol": {"start"}, "li": {"value"}, "dl": nil, "dt": nil, "dd": nil,
+	"table": nil, "caption": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+	"th": {"colspan", "rowspan", "align"}, "td": {"colspan", "rowspan", "align"},
+	"figure": nil, "figcaption": nil,
+}
+
+var globalAttrs = []string{"title", "lang", "dir"}
+
+// inlineTags do not separate words
+var inlineTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "cite": true, "code": true, "del": true, "em": true, "i": true, "ins": true,
+	"kbd": true, "mark": true, "q": true, "s": true, "small": true, "span": true, "strong": true, "sub": true, "sup": true, "u": true,
+}
+
+// voidTags have no content and no end tag
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+var headingTags = []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+
+// urlAttrs are checked against urlSchemes, relative URLs are allowed
+var urlAttrs = map[string]bool{"href": true, "src": true, "cite": true}
+
+var urlSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+const (
+	textToken = iota
+	startTagToken
+	endTagToken
+)
+
+type attribute struct {
+	name, value string
+}
+
+type token struct {
+	kind int
+	// data is the unescaped text of a text token or the lower case name of a tag
+	data  string
+	attrs []attribute
+}
+
+// HTML checks that a document only uses allowed elements, attributes and URLs and rewrites it
+// in a normalized form, which browsers parse as it was checked. Comments are dropped, elements
+// are closed and headings without an id get one for the table of contents.
+func HTML(s string) (string, error) {
+	tokens := tokenize(s)
+	for _, t := range tokens {
+		if err := check(t); err != nil {
+			return "", err
+		}
+	}
+	tokens = balance(tokens)
+	addHeadingIDs(tokens)
+
+	return render(tokens), nil
+}
+
+// Headings lists the headings of a document returned by HTML, in order
+func Headings(s string) []Heading {
+	var headings []Heading
+	tokens := balance(tokenize(s))
+	for i, t := range tokens {
+		if level := headingLevel(t); level > 0 {
+			headings = append(headings, Heading{Level: level, Text: headingText(tokens[i+1:], t.data), ID: getAttr(t, "id")})
+		}
+	}
+
+	return headings
+}
+
+// Text returns the text of a document without its markup, runs of white space made single spaces
+func Text(s string) string {
+	var b strings.Builder
+	for _, t := range tokenize(s) {
+		if t.kind == textToken {
+			b.WriteString(t.data)
+		} else if !inlineTags[t.data] {
+			b.WriteString(" ")
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func check(t token) error {
+	if t.kind == textToken {
+		return nil
+	}
+
+	attrs, ok := allowedTags[t.data]
+	if !ok {
+		return &UnsafeError{fmt.Sprintf("element <%s> is not allowed", t.data)}
+	}
+	if t.kind == endTagToken {
+		return nil
+	}
+
+	for _, a := range t.attrs {
+		if strings.HasPrefix(a.name, "on") {
+			return &UnsafeError{fmt.Sprintf("event handler %s on <%s> is not allowed", a.name, t.data)}
+		}
+		if !contains(attrs, a.name) && !contains(globalAttrs, a.name) {
+			return &UnsafeError{fmt.Sprintf("attribute %s on <%s> is not allowed", a.name, t.data)}
+		}
+		if urlAttrs[a.name] && !isSafeURL(a.value) {
+			return &UnsafeError{fmt.Sprintf("URL %q in %s on <%s> is not allowed", a.value, a.name, t.data)}
+		}
+	}
+
+	return nil
+}
+
+// isSafeURL allows relative URLs and those with a scheme of urlSchemes. Browsers ignore
+// whitespace and control characters in a URL, so "java\tscript:" is a scheme too.
+func isSafeURL(url string) bool {
+	var b strings.Builder
+	for _, r := range url {
+		if r > ' ' && r != 0x7f {
+			b.WriteRune(r)
+		}
+	}
+	cleaned := b.String()
+
+	i := strings.IndexAny(cleaned, ":/?#")
+	if i == -1 || cleaned[i] != ':' {
+		return true
+	}
+
+	return urlSchemes[strings.ToLower(cleaned[:i])]
+}
+
+// addHeadingIDs gives the headings without an id one made of their text, unique in the document
+func addHeadingIDs(tokens []token) {
+	used := make(map[string]bool)
+	for _, t := range tokens {
+		if id := getAttr(t, "id"); id != "" {
+			used[id] = true
+		}
+	}
+
+	// suffixes holds the last number appended to each base, so that repeated headings are numbered in one pass
+	suffixes := make(map[string]int)
+	for i, t := range tokens {
+		if headingLevel(t) == 0 || getAttr(t, "id") != "" {
+			continue
+		}
+
+		base := headingID(headingText(tokens[i+1:], t.data))
+		id := base
+		for used[id] {
+			suffixes[base]++
+			id = base + "-" + strconv.Itoa(suffixes[base])
+		}
+		used[id] = true
+		tokens[i].attrs = append(tokens[i].attrs, attribute{"id", id})
+	}
+}
+
+func headingLevel(t token) int {
+	if t.kind != startTagToken || len(t.data) != 2 || t.data[0] != 'h' || t.data[1] < '1' || t.data[1] > '6' {
+		return 0
+	}
+
+	return int(t.data[1] - '0')
+}
+
+// headingText is the text of the tokens up to the end tag of the heading
+func headingText(tokens []token, name string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		if t.kind == endTagToken && t.data == name {
+			break
+		}
+		if t.kind == textToken {
+			b.WriteString(t.data)
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// headingID keeps the letters and digits of a heading, in lower case and joined by dashes
+func headingID(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "section"
+	}
+
+	return strings.Join(words, "-")
+}
+
+func getAttr(t token, name string) string {
+	for _, a := range t.attrs {
+		if a.name == name {
+			return a.value
+		}
+	}
+
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func render(tokens []token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		switch t.kind {
+		case textToken:
+			b.WriteString(html.EscapeString(t.data))
+		case startTagToken:
+			b.WriteString("<" + t.data)
+			for _, a := range t.attrs {
+				b.WriteString(" " + a.name + `="` + html.EscapeString(a.value) + `"`)
+			}
+			b.WriteString(">")
+		case endTagToken:
+			if !voidTags[t.data] {
+				b.WriteString("</" + t.data + ">")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// tokenize splits a document into text and tags with the tokenizer of browsers. Text that does
+// not form a tag stays text, a tag cut off by the end of the document is dropped, as are comments
+// and doctypes.
+func tokenize(s string) []token {
+	var tokens []token
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, token{kind: textToken, data: text.String()})
+			text.Reset()
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// io.EOF, reading from a string fails in no other way
+			flush()
+			return tokens
+		case html.TextToken:
+			text.Write(z.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			flush()
+			t := z.Token()
+			tag := token{kind: startTagToken, data: t.Data}
+			for _, a := range t.Attr {
+				tag.attrs = append(tag.attrs, attribute{a.Key, a.Val})
+			}
+			tokens = append(tokens, tag)
+		case html.EndTagToken:
+			flush()
+			name, _ := z.TagName()
+			tokens = append(tokens, token{kind: endTagToken, data: string(name)})
+		}
+	}
+}
+
+// balance closes the elements left open at the end of the document or by the end tag of an
+// element around them, and drops end tags without a start tag. A heading closes a heading that
+// is still open, they do not nest.
+func balance(tokens []token) []token {
+	var balanced []token
+	var stack []string
+	open := make(map[string]int)
+	// closeTo closes the innermost open element with the name and those inside it
+	closeTo := func(name string) {
+		if open[name] == 0 {
+			return
+		}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			open[top]--
+			balanced = append(balanced, token{kind: endTagToken, data: top})
+			if top == name {
+				return
+			}
+		}
+	}
+
+	for _, t := range tokens {
+		switch {
+		case t.kind == textToken:
+			balanced = append(balanced, t)
+		case voidTags[t.data]:
+			if t.kind == startTagToken {
+				balanced = append(balanced, t)
+			}
+		case t.kind == startTagToken:
+			if headingLevel(t) > 0 {
+				for _, h := range headingTags {
+					closeTo(h)
+				}
+			}
+			stack = append(stack, t.data)
+			open[t.data]++
+			balanced = append(balanced, t)
+		default:
+			closeTo(t.data)
+		}
+	}
+	for len(stack) > 0 {
+		closeTo(stack[len(stack)-1])
+	}
+
+	return balanced
+}
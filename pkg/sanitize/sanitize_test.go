@@ -0,0 +1,148 @@
+package sanitize
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLRejectsUnsafe(t *testing.T) {
+	tests := []string{
+		`<script>alert(1)</script>`,
+		`<SCRIPT SRC=//evil.example/x.js></SCRIPT>`,
+		`<p>a<script>alert(1)</script></p>`,
+		`<a href="javascript:alert(1)">x</a>`,
+		`<a href="JaVaScRiPt:alert(1)">x</a>`,
+		`<a href=" javascript:alert(1)">x</a>`,
+		"<a href=\"java\tscript:alert(1)\">x</a>",
+		"<a href=\"\x01javascript:alert(1)\">x</a>",
+		`<a href="java&#x09;script:alert(1)">x</a>`,
+		`<a href="java&#9;script:alert(1)">x</a>`,
+		`<a href="java&Tab;script:alert(1)">x</a>`,
+		`<a href="&#106;avascript:alert(1)">x</a>`,
+		`<a href="&#x6A;avascript&colon;alert(1)">x</a>`,
+		`<a href="javascript&#58alert(1)">x</a>`,
+		`<img src="vbscript:msgbox(1)">`,
+		`<img src="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">`,
+		`<blockquote cite="javascript:alert(1)">x</blockquote>`,
+		`<img src=x onerror=alert(1)>`,
+		`<p onclick="alert(1)">x</p>`,
+		`<a href="/" ONMOUSEOVER=alert(1)>x</a>`,
+		`<p/onclick=alert(1)>x</p>`,
+		`<svg onload=alert(1)>`,
+		`<svg><script>alert(1)</script></svg>`,
+		`<math><mi xlink:href="javascript:alert(1)">x</mi></math>`,
+		`<iframe src="https://evil.example"></iframe>`,
+		`<object data="x.swf"></object>`,
+		`<style>body{}</style>`,
+		`<p style="background:url(javascript:alert(1))">x</p>`,
+		`<form action="/x"><input name="q"></form>`,
+		`<textarea><script>alert(1)</script></textarea>`,
+		`<!--><script>alert(1)</script>-->`,
+		`<!-- a --!><script>alert(1)</script>`,
+		`<a href="/" xlink:href="javascript:alert(1)">x</a>`,
+	}
+	for _, s := range tests {
+		out, err := HTML(s)
+		if _, ok := err.(*UnsafeError); !ok {
+			t.Errorf("HTML(%q) = %q, %v, want an *UnsafeError", s, out, err)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`<p>Hello <b>world</b></p>`, `<p>Hello <b>world</b></p>`},
+		{`<p>a < b & c > d</p>`, `<p>a &lt; b &amp; c &gt; d</p>`},
+		{`<P TITLE=x>a</P>`, `<p title="x">a</p>`},
+		{`<div class="note">a</div>`, `<div class="note">a</div>`},
+		{`<a href="https://example.com/?a=1&amp;b=2" title='say "hi"'>x</a>`, `<a href="https://example.com/?a=1&amp;b=2" title="say &#34;hi&#34;">x</a>`},
+		{`<a href="mailto:a@example.com">a</a><a href="#top">b</a><a href="/x:y?q">c</a>`, `<a href="mailto:a@example.com">a</a><a href="#top">b</a><a href="/x:y?q">c</a>`},
+		{`<img src="/a.png" alt="a"><br/><hr>`, `<img src="/a.png" alt="a"><br><hr>`},
+		// Comments are dropped, an unclosed one swallows the rest of the document as in browsers
+		{`<p>a<!-- b -->c</p>`, `<p>ac</p>`},
+		{`<p>a</p><!-- <script>alert(1)</script>`, `<p>a</p>`},
+		{`<!DOCTYPE html><p>a</p>`, `<p>a</p>`},
+		// A tag cut off by the end of the document is dropped, "<" that starts no tag is text
+		{`<p>a</p><a href="/x" `, `<p>a</p>`},
+		{`1 <2 and 3<>4`, `1 &lt;2 and 3&lt;&gt;4`},
+		// Elements are balanced
+		{`<a href="/x">link`, `<a href="/x">link</a>`},
+		{`</div><p>x</p></p>`, `<p>x</p>`},
+		{`<b><i>x</b>y`, `<b><i>x</i></b>y`},
+		{`<ul><li>a<li>b</ul>`, `<ul><li>a<li>b</li></li></ul>`},
+		{`<br></br></img>`, `<br>`},
+		{`<p/>x`, `<p>x</p>`},
+		// Headings get ids and do not nest
+		{`<h1>Hello, World!</h1><h2 id="x">X</h2>`, `<h1 id="hello-world">Hello, World!</h1><h2 id="x">X</h2>`},
+		{`<h1>A</h1><h1>A</h1><h1 id="a-1">B</h1><h1>A</h1>`, `<h1 id="a">A</h1><h1 id="a-2">A</h1><h1 id="a-1">B</h1><h1 id="a-3">A</h1>`},
+		{`<h1>A<h2>B</h2>`, `<h1 id="a">A</h1><h2 id="b">B</h2>`},
+		{`<h2>中文 标题</h2><h3>!!</h3>`, `<h2 id="中文-标题">中文 标题</h2><h3 id="section">!!</h3>`},
+	}
+	for _, tt := range tests {
+		got, err := HTML(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("HTML(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestHTMLIsStable(t *testing.T) {
+	in := `<h1>T</h1><p>a <b>b<i>c</b> &amp; <a href="/x?a=1&b=2">d</p><ul><li>e</ul></div>`
+	once, err := HTML(in)
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	twice, err := HTML(once)
+	if err != nil || twice != once {
+		t.Errorf("HTML(HTML(s)) = %q, %v, want %q", twice, err, once)
+	}
+}
+
+func TestHeadings(t *testing.T) {
+	got := Headings(`<h1 id="a">A <em>b</em></h1><p>x</p><h2 id="c">C</h2>`)
+	want := []Heading{{1, "A b", "a"}, {2, "C", "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Headings() = %v, want %v", got, want)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := map[string]string{
+		`<p>Hello <b>Wor</b>ld</p><p>Next &amp; more</p>`: "Hello World Next & more",
+		"<ul>\n<li>a</li>\n<li>b</li>\n</ul>":             "a b",
+		`a&lt;b<br>c`:                                     "a<b c",
+		`<p>a<!-- b -->c</p>`:                             "ac",
+		``:                                                "",
+	}
+	for in, want := range tests {
+		if got := Text(in); got != want {
+			t.Errorf("Text(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestLinearTime guards against inputs that used to take seconds, each must take well under one
+func TestLinearTime(t *testing.T) {
+	tests := map[string]string{
+		"unterminated tags":       strings.Repeat("<a ", 20000),
+		"unterminated attributes": strings.Repeat(`<a href="`, 10000),
+		"unterminated comments":   strings.Repeat("<!--", 20000),
+		"stray end tags":          strings.Repeat("<b>", 20000) + strings.Repeat("</i>", 20000),
+		"nested elements":         strings.Repeat("<p><b>", 10000) + strings.Repeat("</p>", 10000),
+		"repeated headings":       strings.Repeat("<h1>", 20000),
+		"comments between text":   strings.Repeat("a<!---->", 20000),
+	}
+	for name, s := range tests {
+		start := time.Now()
+		HTML(s)
+		Headings(s)
+		Text(s)
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("%s: took %v", name, d)
+		}
+	}
+}
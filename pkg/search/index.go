@@ -0,0 +1,131 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Weights of a term occurring in each field of a document
+const (
+	TITLE_WEIGHT   = 3
+	DESC_WEIGHT    = 2
+	CONTENT_WEIGHT = 1
+)
+
+// Index is an inverted index kept in memory. It needs no database, but every server
+// process has its own copy, so it only sees the changes made through that process.
+type Index struct {
+	mu sync.RWMutex
+	// postings holds the weighted frequency of each term in each document
+	postings map[string]map[int]float64
+	// terms lists the terms of each document, to remove it again
+	terms map[int][]string
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[int]float64),
+		terms:    make(map[int][]string),
+	}
+}
+
+// Index adds or replaces the text of an article
+func (idx *Index) Index(doc Document) error {
+	freqs := make(map[string]float64)
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{doc.Title, TITLE_WEIGHT},
+		{doc.Desc, DESC_WEIGHT},
+		{doc.Content, CONTENT_WEIGHT},
+	}
+	for _, f := range fields {
+		tokenize(f.text, func(term string) {
+			freqs[term] += f.weight
+		})
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(doc.ID)
+	terms := make([]string, 0, len(freqs))
+	for term, freq := range freqs {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[int]float64)
+		}
+		idx.postings[term][doc.ID] = freq
+		terms = append(terms, term)
+	}
+	idx.terms[doc.ID] = terms
+
+	return nil
+}
+
+// Remove drops an article
+func (idx *Index) Remove(id int) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+	return nil
+}
+
+func (idx *Index) remove(id int) {
+	for _, term := range idx.terms[id] {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.terms, id)
+}
+
+// Search returns up to limit articles containing every term, ranked by TF-IDF
+func (idx *Index) Search(terms []string, limit int) ([]Hit, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Start from the rarest term, every other one only narrows the candidates
+	sorted := append([]string(nil), terms...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(idx.postings[sorted[i]]) < len(idx.postings[sorted[j]])
+	})
+
+	total := float64(len(idx.terms))
+	var hits []Hit
+	for id := range idx.postings[sorted[0]] {
+		score := 0.0
+		for _, term := range sorted {
+			freq, ok := idx.postings[term][id]
+			if !ok {
+				score = -1
+				break
+			}
+			idf := math.Log(1 + total/float64(len(idx.postings[term])))
+			score += (1 + math.Log(freq)) * idf
+		}
+		if score >= 0 {
+			hits = append(hits, Hit{ID: id, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestIndex(t *testing.T, docs ...Document) *Index {
+	idx := NewIndex()
+	for _, doc := range docs {
+		if err := idx.Index(doc); err != nil {
+			t.Fatalf("Index(%d): %v", doc.ID, err)
+		}
+	}
+
+	return idx
+}
+
+func hitIDs(t *testing.T, idx *Index, query string, limit int) []int {
+	hits, err := idx.Search(Terms(query), limit)
+	if err != nil {
+		t.Fatalf("Search(%q): %v", query, err)
+	}
+
+	var ids []int
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{ID: 1, Title: "Gin tutorial", Desc: "Routing", Content: "Gin is a web framework written in Go."},
+		Document{ID: 2, Title: "Go modules", Desc: "Gin", Content: "Modules replaced GOPATH."},
+		Document{ID: 3, Title: "Databases", Desc: "GORM", Content: "GORM works with Gin and Go."},
+		Document{ID: 4, Title: "中文标题", Content: "用 Gin 写一个博客"},
+	)
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		// Matches in the title weigh more than in the description, which weigh more than in the content,
+		// ties go to the latest article
+		{"gin", []int{1, 2, 4, 3}},
+		{"go", []int{2, 3, 1}},
+		// Every term must occur
+		{"gin go", []int{2, 1, 3}},
+		{"gin gorm", []int{3}},
+		{"gin missing", nil},
+		{"中文", []int{4}},
+		{"文标", []int{4}},
+		{"标中", nil},
+		{"博客 gin", []int{4}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := hitIDs(t, idx, tt.query, 10); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if got := hitIDs(t, idx, "gin", 2); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Search() with a limit = %v, want [1 2]", got)
+	}
+}
+
+func TestIndexRarerTermsWeighMore(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{ID: 1, Content: "common common rare"},
+		Document{ID: 2, Content: "common rare rare"},
+		Document{ID: 3, Content: "common"},
+	)
+
+	if got := hitIDs(t, idx, "common rare", 10); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("Search() = %v, want [2 1]", got)
+	}
+}
+
+func TestIndexReplaceAndRemove(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{ID: 1, Title: "Gin"},
+		Document{ID: 2, Title: "Echo"},
+	)
+
+	if err := idx.Index(Document{ID: 1, Title: "Fiber"}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if got := hitIDs(t, idx, "gin", 10); got != nil {
+		t.Errorf("Search() of replaced text = %v, want none", got)
+	}
+	if got := hitIDs(t, idx, "fiber", 10); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("Search() of new text = %v, want [1]", got)
+	}
+
+	if err := idx.Remove(1); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if got := hitIDs(t, idx, "fiber", 10); got != nil {
+		t.Errorf("Search() of a removed article = %v, want none", got)
+	}
+	if len(idx.postings["fiber"]) != 0 || idx.terms[1] != nil {
+		t.Error("Remove() left postings of the article")
+	}
+	if got := hitIDs(t, idx, "echo", 10); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Search() of another article = %v, want [2]", got)
+	}
+}
@@ -0,0 +1,177 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// MAX_TERMS bounds the work of a single query
+const MAX_TERMS = 10
+
+// Document is the searchable text of an article
+type Document struct {
+	ID      int
+	Title   string
+	Desc    string
+	Content string
+}
+
+// Hit is an article matching a query and its relevance, higher is better
+type Hit struct {
+	ID    int
+	Score float64
+}
+
+// Engine finds the articles matching a query
+type Engine interface {
+	// Index adds or replaces the text of an article
+	Index(doc Document) error
+	// Remove drops an article
+	Remove(id int) error
+	// Search returns up to limit articles containing every term, best match first
+	Search(terms []string, limit int) ([]Hit, error)
+}
+
+// Terms splits a query into search terms, at most MAX_TERMS of them, as documents are split
+// when they are indexed. Duplicates are dropped.
+func Terms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	tokenize(query, func(term string) {
+		if !seen[term] && len(terms) < MAX_TERMS {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	})
+
+	return terms
+}
+
+// tokenize calls emit with every lower case term of text, repetitions included. Letters and
+// digits form words, runs of Chinese, Japanese and Korean characters, which are not separated
+// by spaces, are split into overlapping pairs ("中文标题" into "中文", "文标" and "标题").
+func tokenize(text string, emit func(string)) {
+	var word, cjk []rune
+	flush := func() {
+		if len(word) > 0 {
+			emit(string(word))
+			word = word[:0]
+		}
+		if len(cjk) == 1 {
+			emit(string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			emit(string(cjk[i : i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			if len(word) > 0 {
+				flush()
+			}
+			cjk = append(cjk, r)
+		case isWordRune(r):
+			if len(cjk) > 0 {
+				flush()
+			}
+			word = append(word, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isWordRune(r rune) bool {
+	return !isCJK(r) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// Highlight escapes text for HTML and wraps the occurrences of the terms in <em> tags. With a
+// width above 0 it is cut to a snippet of about width characters around the first occurrence.
+func Highlight(text string, terms []string, width int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		// Words only match whole words, like in the index
+		word := !isCJK(t[0])
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if !hasPrefix(lower[i:], t) {
+				continue
+			}
+			if word && (i > 0 && isWordRune(lower[i-1]) || i+len(t) < len(lower) && isWordRune(lower[i+len(t)])) {
+				continue
+			}
+			for j := i; j < i+len(t); j++ {
+				marked[j] = true
+			}
+			if first == -1 || i < first {
+				first = i
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if width > 0 && len(runes) > width {
+		// Show a little of what leads up to the first occurrence
+		if first > width/4 {
+			start = first - width/4
+		}
+		if end = start + width; end > len(runes) {
+			end = len(runes)
+			start = end - width
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString("<em>" + html.EscapeString(string(runes[i:j])) + "</em>")
+		} else {
+			b.WriteString(html.EscapeString(string(runes[i:j])))
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+
+	return true
+}
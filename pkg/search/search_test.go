@@ -0,0 +1,63 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Gin Web", []string{"gin", "web"}},
+		{"go, GO; go!", []string{"go"}},
+		{"中文标题", []string{"中文", "文标", "标题"}},
+		{"Gin教程", []string{"gin", "教程"}},
+		{"字", []string{"字"}},
+		{"ひらがな カタカナ 한국어", []string{"ひら", "らが", "がな", "カタ", "タカ", "カナ", "한국", "국어"}},
+		{"a1 b2", []string{"a1", "b2"}},
+		{"  ,;!  ", nil},
+		{"a b c d e f g h i j k l", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+	}
+	for _, tt := range tests {
+		if got := Terms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"Gin and gin", []string{"gin"}, "<em>Gin</em> and <em>gin</em>"},
+		{"Going gin", []string{"go", "gin"}, "Going <em>gin</em>"},
+		{"<script>alert(1)</script> gin", []string{"gin"}, "&lt;script&gt;alert(1)&lt;/script&gt; <em>gin</em>"},
+		{`"a" & 'b'`, []string{"a", "b"}, `&#34;<em>a</em>&#34; &amp; &#39;<em>b</em>&#39;`},
+		{"<em>", []string{"em"}, "&lt;<em>em</em>&gt;"},
+		{"学习中文标题", []string{"中文", "文标"}, "学习<em>中文标</em>题"},
+		{"no match", []string{"gin"}, "no match"},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.text, tt.terms, 0); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.terms, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	text := strings.Repeat("lorem ", 50) + "gin " + strings.Repeat("ipsum ", 50)
+	got := Highlight(text, []string{"gin"}, 40)
+	want := "…rem lorem <em>gin</em> ipsum ipsum ipsum ipsum ip…"
+	if got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+
+	if got := Highlight("gin <b>", []string{"gin"}, 40); got != "<em>gin</em> &lt;b&gt;" {
+		t.Errorf("Highlight() of a short text = %q, want it whole", got)
+	}
+}
@@ -12,6 +12,53 @@ type App struct {
 	PageSize  int
 	PrefixUrl string
 
+	JwtSigningMethod    string
+	JwtSigningKey       string
+	JwtVerificationKeys []string
+
+	AccessTokenExpire  time.Duration
+	RefreshTokenExpire time.Duration
+
+	SessionStoreFailurePolicy string
+
+	LoginMaxAttempts   int
+	LoginIPMaxAttempts int
+	LoginAttemptWindow time.Duration
+	LoginLockoutBase   time.Duration
+	LoginLockoutMax    time.Duration
+
+	MfaChallengeExpire time.Duration
+
+	PasswordResetExpire        time.Duration
+	PasswordResetUrl           string
+	PasswordResetMaxRequests   int
+	PasswordResetIPMaxRequests int
+	PasswordResetRequestWindow time.Duration
+
+	PasswordMinLength      int
+	PasswordMinCharClasses int
+	PasswordBreachListFile string
+	PasswordHistory        int
+	BcryptCost             int
+
+	PublishSchedulerInterval time.Duration
+
+	TrashRetentionDays int
+	TrashPurgeInterval time.Duration
+
+	SlugPinyinFile string
+
+	SearchEngine string
+
+	ViewDedupWindow   time.Duration
+	ViewFlushInterval time.Duration
+
+	FeedTitle       string
+	FeedDescription string
+	FeedArticlePath string
+	FeedSize        int
+	FeedCacheExpire time.Duration
+
 	RuntimeRootPath string
 
 	ImageSavePath  string
@@ -60,6 +107,18 @@ type Redis struct {
 
 var RedisSetting = &Redis{}
 
+type Mail struct {
+	Driver   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	SavePath string
+}
+
+var MailSetting = &Mail{}
+
 var cfg *ini.File
 
 // Setup initialize the configuration instance
@@ -74,8 +133,22 @@ func Setup() {
 	mapTo("server", ServerSetting)
 	mapTo("database", DatabaseSetting)
 	mapTo("redis", RedisSetting)
+	mapTo("mail", MailSetting)
 
 	AppSetting.ImageMaxSize = AppSetting.ImageMaxSize * 1024 * 1024
+	AppSetting.AccessTokenExpire = AppSetting.AccessTokenExpire * time.Second
+	AppSetting.RefreshTokenExpire = AppSetting.RefreshTokenExpire * time.Second
+	AppSetting.LoginAttemptWindow = AppSetting.LoginAttemptWindow * time.Second
+	AppSetting.LoginLockoutBase = AppSetting.LoginLockoutBase * time.Second
+	AppSetting.LoginLockoutMax = AppSetting.LoginLockoutMax * time.Second
+	AppSetting.MfaChallengeExpire = AppSetting.MfaChallengeExpire * time.Second
+	AppSetting.PasswordResetExpire = AppSetting.PasswordResetExpire * time.Second
+	AppSetting.PasswordResetRequestWindow = AppSetting.PasswordResetRequestWindow * time.Second
+	AppSetting.PublishSchedulerInterval = AppSetting.PublishSchedulerInterval * time.Second
+	AppSetting.TrashPurgeInterval = AppSetting.TrashPurgeInterval * time.Second
+	AppSetting.ViewDedupWindow = AppSetting.ViewDedupWindow * time.Second
+	AppSetting.ViewFlushInterval = AppSetting.ViewFlushInterval * time.Second
+	AppSetting.FeedCacheExpire = AppSetting.FeedCacheExpire * time.Second
 	ServerSetting.ReadTimeout = ServerSetting.ReadTimeout * time.Second
 	ServerSetting.WriteTimeout = ServerSetting.WriteTimeout * time.Second
 	RedisSetting.IdleTimeout = RedisSetting.IdleTimeout * time.Second
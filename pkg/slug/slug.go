@@ -0,0 +1,187 @@
+package slug
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	gopinyin "github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+// MAX_LENGTH leaves room in the 100 characters of the slug column for a "-N" suffix
+const MAX_LENGTH = 90
+
+var validSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var (
+	// pinyin maps Chinese characters to their toneless pinyin
+	pinyin     map[rune]string
+	pinyinOnce sync.Once
+)
+
+// Setup Initialize the slug generator with the built-in pinyin dictionary, overridden by
+// the readings in SlugPinyinFile if one is configured
+func Setup() {
+	dict := builtinPinyin()
+	if path := setting.AppSetting.SlugPinyinFile; path != "" {
+		custom, err := loadPinyin(path)
+		if err != nil {
+			log.Fatalf("slug.Setup, fail to load the pinyin dictionary: %v", err)
+		}
+		for char, reading := range custom {
+			dict[char] = reading
+		}
+	}
+
+	pinyin = dict
+}
+
+// Make turns a title into lower case words joined by dashes. Accents are dropped and Chinese
+// characters are written in pinyin; other characters are skipped.
+// The result is empty if nothing of the title could be transliterated.
+func Make(title string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	dict := getPinyin()
+	for _, r := range norm.NFD.String(strings.ToLower(title)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Accents were split off by the decomposition
+		case dict[r] != "":
+			flush()
+			words = append(words, dict[r])
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	slug := strings.Join(words, "-")
+	for len(slug) > MAX_LENGTH {
+		i := strings.LastIndex(slug[:MAX_LENGTH+1], "-")
+		if i <= 0 {
+			return slug[:MAX_LENGTH]
+		}
+		slug = slug[:i]
+	}
+
+	return slug
+}
+
+// Hash is the fallback slug of a title that Make cannot transliterate
+func Hash(title string) string {
+	return "a-" + util.EncodeMD5(title)[:8]
+}
+
+// WithSuffix numbers a slug that is already taken, n starting at 2
+func WithSuffix(slug string, n int) string {
+	return slug + "-" + strconv.Itoa(n)
+}
+
+// IsValid checks that a slug chosen by a user is lower case words joined by single dashes
+func IsValid(slug string) bool {
+	return len(slug) <= MAX_LENGTH && validSlug.MatchString(slug)
+}
+
+// getPinyin returns the dictionary set up by Setup, or the built-in one if Setup was not called
+func getPinyin() map[rune]string {
+	pinyinOnce.Do(func() {
+		if pinyin == nil {
+			pinyin = builtinPinyin()
+		}
+	})
+
+	return pinyin
+}
+
+// builtinPinyin is the dictionary of go-pinyin, generated from the pinyin-data project.
+// The first reading of a character is used.
+func builtinPinyin() map[rune]string {
+	dict := make(map[rune]string, len(gopinyin.PinyinDict))
+	for code, readings := range gopinyin.PinyinDict {
+		if reading := toneless(strings.Split(readings, ",")[0]); reading != "" {
+			dict[rune(code)] = reading
+		}
+	}
+
+	return dict
+}
+
+// loadPinyin reads a dictionary in the format of the pinyin-data project,
+// "U+4E2D: zhōng,zhòng  # 中", or one character and its readings per line, "中 zhōng,zhòng".
+// The first reading of a character is used.
+func loadPinyin(path string) (map[rune]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dict := make(map[rune]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0])
+		fields := strings.Fields(strings.Replace(line, ":", " ", 1))
+		if len(fields) < 2 {
+			continue
+		}
+
+		var char rune
+		if strings.HasPrefix(fields[0], "U+") {
+			code, err := strconv.ParseInt(fields[0][2:], 16, 32)
+			if err != nil {
+				continue
+			}
+			char = rune(code)
+		} else {
+			char = []rune(fields[0])[0]
+		}
+
+		if reading := toneless(strings.Split(fields[1], ",")[0]); reading != "" {
+			dict[char] = reading
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return dict, nil
+}
+
+// toneless drops the tone marks of a pinyin reading, writing ü as v
+func toneless(reading string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(reading)) {
+		switch {
+		case r == '\u0308':
+			// The diaeresis of ü
+			s := b.String()
+			if strings.HasSuffix(s, "u") {
+				b.Reset()
+				b.WriteString(s[:len(s)-1] + "v")
+			}
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
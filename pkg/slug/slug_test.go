@@ -0,0 +1,77 @@
+package slug
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  Go 1.24 released  ", "go-1-24-released"},
+		{"Café déjà vu", "cafe-deja-vu"},
+		{"Gin教程", "gin-jiao-cheng"},
+		{"中文标题", "zhong-wen-biao-ti"},
+		{"绿色", "lv-se"},
+		{"🎉🎉", ""},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.title); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestMakeTruncatesAtWords(t *testing.T) {
+	title := strings.Repeat("word ", 30)
+	got := Make(title)
+	if len(got) > MAX_LENGTH || strings.HasSuffix(got, "-") || !strings.HasSuffix(got, "word") {
+		t.Errorf("Make(%q) = %q, want whole words within %d characters", title, got, MAX_LENGTH)
+	}
+}
+
+func TestSetupOverridesReadings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pinyin.txt")
+	dict := "U+4E2D: zhòng,zhōng  # 中\n行 háng,xíng\n"
+	if err := os.WriteFile(path, []byte(dict), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := setting.AppSetting.SlugPinyinFile
+	setting.AppSetting.SlugPinyinFile = path
+	defer func() {
+		setting.AppSetting.SlugPinyinFile = old
+		Setup()
+	}()
+	Setup()
+
+	if got, want := Make("中国银行"), "zhong-guo-yin-hang"; got != want {
+		t.Errorf("Make() = %q, want %q", got, want)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := map[string]bool{
+		"hello-world":           true,
+		"a1":                    true,
+		"Hello":                 false,
+		"hello--world":          false,
+		"-hello":                false,
+		"hello_world":           false,
+		strings.Repeat("a", 91): false,
+		strings.Repeat("a", 90): true,
+		"":                      false,
+	}
+	for slug, want := range tests {
+		if got := IsValid(slug); got != want {
+			t.Errorf("IsValid(%q) = %v, want %v", slug, got, want)
+		}
+	}
+}
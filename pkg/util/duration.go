@@ -0,0 +1,12 @@
+package util
+
+import "time"
+
+// Seconds converts a duration to whole seconds for Redis timeouts, at least 1 as 0 means no timeout
+func Seconds(d time.Duration) int {
+	if s := int(d / time.Second); s > 0 {
+		return s
+	}
+
+	return 1
+}
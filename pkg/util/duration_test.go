@@ -0,0 +1,22 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeconds(t *testing.T) {
+	tests := map[time.Duration]int{
+		time.Hour:               3600,
+		1500 * time.Millisecond: 1,
+		time.Second:             1,
+		time.Millisecond:        1,
+		0:                       1,
+		-time.Minute:            1,
+	}
+	for d, want := range tests {
+		if got := Seconds(d); got != want {
+			t.Errorf("Seconds(%v) = %d, want %d", d, got, want)
+		}
+	}
+}
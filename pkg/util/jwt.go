@@ -1,10 +1,14 @@
 package util
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
 )
 
@@ -13,50 +17,182 @@ var jwtSecret []byte
 type Claims struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Role     string `json:"role"`
+	Family   string `json:"fam,omitempty"`
 	jwt.StandardClaims
+
+	// ClientID is set on tokens issued to an OAuth2 client with the client_credentials grant
+	ClientID string `json:"client_id,omitempty"`
+	// Scopes narrows down the permissions of an API key or grants those of an OAuth2 client
+	Scopes []string `json:"scopes,omitempty"`
+
+	// ApiKeyID is only set when the request authenticated with an API key
+	ApiKeyID int `json:"-"`
 }
 
-// GenerateToken generate tokens used for auth and store in Redis
-func GenerateToken(username, password string) (string, error) {
-	nowTime := time.Now()
-	expireTime := nowTime.Add(3 * time.Hour)
+// HasPermission checks the role of the claims, narrowed down to the scopes of an API key.
+// OAuth2 clients have no role, their scopes alone decide.
+func (c *Claims) HasPermission(permission string) bool {
+	if c.ClientID == "" && !rbac.HasPermission(c.Role, permission) {
+		return false
+	}
+	if c.ClientID == "" && len(c.Scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range c.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
 
+	return false
+}
+
+// TokenPair is an access token together with the refresh token that renews it
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+// GenerateToken generate tokens used for auth and store the session in Redis
+func GenerateToken(username, role, device, ip string) (string, error) {
+	token, _, err := generateToken(username, role, "", device, ip)
+	return token, err
+}
+
+// GenerateClientToken generate an access token for an OAuth2 client, there is no refresh token
+// for the client_credentials grant as the client can always authenticate again
+func GenerateClientToken(clientID string, scopes []string, ip string) (string, error) {
 	claims := Claims{
-		username,
-		"", // Don't store password in token for security
-		jwt.StandardClaims{
-			ExpiresAt: expireTime.Unix(),
-			Issuer:    "gin-blog",
-		},
+		Username: GetClientSubject(clientID),
+		ClientID: clientID,
+		Scopes:   scopes,
 	}
 
-	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, err := tokenClaims.SignedString(jwtSecret)
+	token, _, err := issueToken(&claims, "oauth2 client", ip)
+	return token, err
+}
+
+// GetClientSubject returns the username tokens of an OAuth2 client are issued under,
+// which cannot clash with a registered username
+func GetClientSubject(clientID string) string {
+	return "client:" + clientID
+}
+
+// GenerateTokenPair generate an access token and a refresh token opening a new token family
+func GenerateTokenPair(username, role, device, ip string) (*TokenPair, error) {
+	family, err := newTokenID()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Store token in Redis (fallback enabled)
-	err = jwt_redis_service.StoreToken(username, token)
+	return generateTokenPair(username, role, family, device, ip)
+}
+
+// RotateRefreshToken consume a refresh token, returning what it was issued for
+func RotateRefreshToken(refreshToken string) (*jwt_redis_service.RefreshToken, error) {
+	return jwt_redis_service.RotateRefreshToken(EncodeSHA256(refreshToken))
+}
+
+// RenewTokenPair generate the token pair replacing a rotated refresh token, in the same family
+func RenewTokenPair(rotated *jwt_redis_service.RefreshToken, role, device, ip string) (*TokenPair, error) {
+	return generateTokenPair(rotated.Username, role, rotated.Family, device, ip)
+}
+
+func generateTokenPair(username, role, family, device, ip string) (*TokenPair, error) {
+	accessToken, jti, err := generateToken(username, role, family, device, ip)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return token, nil
+	refreshToken, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = jwt_redis_service.StoreRefreshToken(EncodeSHA256(refreshToken), &jwt_redis_service.RefreshToken{
+		Username:  username,
+		Family:    family,
+		SessionID: jti,
+		ExpiresAt: time.Now().Add(setting.AppSetting.RefreshTokenExpire).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(setting.AppSetting.AccessTokenExpire / time.Second),
+	}, nil
 }
 
-// ParseToken parsing token and validate against Redis
-func ParseToken(token string) (*Claims, error) {
-	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+// generateToken sign an access token and store its session in Redis, returning the token and its jti
+func generateToken(username, role, family, device, ip string) (string, string, error) {
+	claims := Claims{
+		Username: username,
+		Password: "", // Don't store password in token for security
+		Role:     role,
+		Family:   family,
+	}
+
+	return issueToken(&claims, device, ip)
+}
+
+// issueToken fill in the standard claims, sign them and store the session in Redis
+func issueToken(claims *Claims, device, ip string) (string, string, error) {
+	nowTime := time.Now()
+	expireTime := nowTime.Add(setting.AppSetting.AccessTokenExpire)
+
+	jti, err := newTokenID()
+	if err != nil {
+		return "", "", err
+	}
+
+	claims.StandardClaims = jwt.StandardClaims{
+		Id:        jti,
+		IssuedAt:  nowTime.Unix(),
+		ExpiresAt: expireTime.Unix(),
+		Issuer:    "gin-blog",
+	}
+
+	token, err := signToken(claims)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Store session in Redis (fallback enabled)
+	err = jwt_redis_service.StoreSession(&jwt_redis_service.Session{
+		ID:        jti,
+		Username:  claims.Username,
+		Device:    device,
+		IP:        ip,
+		IssuedAt:  nowTime.Unix(),
+		ExpiresAt: expireTime.Unix(),
+		Family:    claims.Family,
 	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, jti, nil
+}
+
+// ParseToken parsing token and validate its session against Redis
+func ParseToken(token string) (*Claims, error) {
+	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, getVerificationKey)
 
 	if tokenClaims != nil {
 		if claims, ok := tokenClaims.Claims.(*Claims); ok && tokenClaims.Valid {
-			// Validate token against Redis store
-			isValid, err := jwt_redis_service.IsTokenValid(claims.Username, token)
-			if err != nil || !isValid {
-				return nil, jwt.NewValidationError("token not found in store or invalid", jwt.ValidationErrorClaimsInvalid)
+			// Validate session against Redis store
+			isValid, err := jwt_redis_service.IsSessionValid(claims.Username, claims.Id, claims.Family)
+			if err != nil {
+				return nil, err
+			}
+			if !isValid {
+				return nil, jwt.NewValidationError("session not found in store or invalid", jwt.ValidationErrorClaimsInvalid)
 			}
 			return claims, nil
 		}
@@ -65,7 +201,17 @@ func ParseToken(token string) (*Claims, error) {
 	return nil, err
 }
 
-// InvalidateToken removes token from Redis
-func InvalidateToken(username string) error {
-	return jwt_redis_service.DeleteToken(username)
+// InvalidateToken removes the session of a token from Redis
+func InvalidateToken(claims *Claims) error {
+	return jwt_redis_service.RevokeToken(claims.Username, claims.Id, claims.Family, claims.ExpiresAt)
+}
+
+// newTokenID generates a random jti
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,221 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// jwtKey is a key tokens are signed or verified with
+type jwtKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// Private is only set for the signing key
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+var (
+	signingKey       *jwtKey
+	verificationKeys map[string]*jwtKey
+)
+
+// setupKeys loads the signing key and the keys still accepted for verification.
+// HS256 signs with JwtSecret; RS256 and ES256 sign with the private key in JwtSigningKey,
+// and also accept tokens signed by the keys in JwtVerificationKeys, so that a retired
+// key keeps verifying the tokens it issued until they expire.
+func setupKeys() error {
+	verificationKeys = make(map[string]*jwtKey)
+
+	method := strings.ToUpper(setting.AppSetting.JwtSigningMethod)
+	switch method {
+	case "", jwt.SigningMethodHS256.Alg():
+		signingKey = &jwtKey{Method: jwt.SigningMethodHS256}
+		return nil
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg():
+	default:
+		return fmt.Errorf("unsupported JwtSigningMethod %q", setting.AppSetting.JwtSigningMethod)
+	}
+
+	key, err := loadJWTKey(setting.AppSetting.JwtSigningKey)
+	if err != nil {
+		return err
+	}
+	if key.Private == nil {
+		return errors.New("JwtSigningKey must be a private key")
+	}
+	if key.Method.Alg() != method {
+		return fmt.Errorf("JwtSigningKey is a %s key, JwtSigningMethod is %s", key.Method.Alg(), method)
+	}
+	signingKey = key
+	verificationKeys[key.ID] = key
+
+	for _, path := range setting.AppSetting.JwtVerificationKeys {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+
+		key, err := loadJWTKey(path)
+		if err != nil {
+			return err
+		}
+		verificationKeys[key.ID] = &jwtKey{ID: key.ID, Method: key.Method, Public: key.Public}
+	}
+
+	return nil
+}
+
+// loadJWTKey reads an RSA or P-256 EC key, private or public, from a PEM file
+func loadJWTKey(path string) (*jwtKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &jwtKey{}
+	if private, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, private, &private.PublicKey
+	} else if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		key.Method, key.Public = jwt.SigningMethodRS256, public
+	} else if private, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		key.Method, key.Private, key.Public = jwt.SigningMethodES256, private, &private.PublicKey
+	} else if public, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		key.Method, key.Public = jwt.SigningMethodES256, public
+	} else {
+		return nil, fmt.Errorf("%s: not an RSA or EC key in PEM format", path)
+	}
+
+	if ec, ok := key.Public.(*ecdsa.PublicKey); ok && ec.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("%s: ES256 requires a P-256 key", path)
+	}
+
+	jwk := toJWK(key)
+	key.ID = jwkThumbprint(&jwk)
+
+	return key, nil
+}
+
+// getVerificationKey is the jwt.Keyfunc picking the key by the kid header
+func getVerificationKey(token *jwt.Token) (interface{}, error) {
+	if signingKey.Method == jwt.SigningMethodHS256 {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return jwtSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := verificationKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method != key.Method {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.Public, nil
+}
+
+// signToken signs claims with the current signing key
+func signToken(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	if signingKey.Method == jwt.SigningMethodHS256 {
+		return token.SignedString(jwtSecret)
+	}
+
+	token.Header["kid"] = signingKey.ID
+	return token.SignedString(signingKey.Private)
+}
+
+// GetJWKS returns the public keys tokens can be verified with, empty for HS256
+func GetJWKS() []JWK {
+	keys := make([]JWK, 0, len(verificationKeys))
+	// The signing key goes first, the order of the rest does not matter
+	if signingKey != nil && signingKey.ID != "" {
+		keys = append(keys, toJWK(signingKey))
+	}
+	for id, key := range verificationKeys {
+		if id != signingKey.ID {
+			keys = append(keys, toJWK(key))
+		}
+	}
+
+	return keys
+}
+
+func toJWK(key *jwtKey) JWK {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeJWKInt(public.N, 0)
+		jwk.E = encodeJWKInt(big.NewInt(int64(public.E)), 0)
+	case *ecdsa.PublicKey:
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = encodeJWKInt(public.X, 32)
+		jwk.Y = encodeJWKInt(public.Y, 32)
+	}
+
+	return jwk
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint of a key, used as its kid
+func jwkThumbprint(jwk *JWK) string {
+	var members interface{}
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	}
+
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// encodeJWKInt base64url encodes a big-endian integer, left padded to size bytes
+func encodeJWKInt(i *big.Int, size int) string {
+	b := i.Bytes()
+	if len(b) < size {
+		b = append(make([]byte, size-len(b)), b...)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
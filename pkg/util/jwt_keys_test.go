@@ -0,0 +1,214 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// writeKeys generates a key pair, returning the paths of its private and public PEM files
+func writeKeys(t *testing.T, alg string) (string, string) {
+	t.Helper()
+
+	var (
+		private  []byte
+		blockTyp string
+		public   interface{}
+	)
+	switch alg {
+	case "RS256":
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, blockTyp, public = x509.MarshalPKCS1PrivateKey(key), "RSA PRIVATE KEY", &key.PublicKey
+	case "ES256":
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, blockTyp, public = der, "EC PRIVATE KEY", &key.PublicKey
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath, publicPath := filepath.Join(dir, "private.pem"), filepath.Join(dir, "public.pem")
+	if err := ioutil.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: blockTyp, Bytes: private}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return privatePath, publicPath
+}
+
+// useKeys sets up the signing and verification keys, restoring the previous ones after the test
+func useKeys(t *testing.T, method, signing string, verification ...string) error {
+	t.Helper()
+
+	old, oldSigning, oldVerification := *setting.AppSetting, signingKey, verificationKeys
+	t.Cleanup(func() {
+		*setting.AppSetting = old
+		signingKey, verificationKeys = oldSigning, oldVerification
+	})
+
+	setting.AppSetting.JwtSigningMethod = method
+	setting.AppSetting.JwtSigningKey = signing
+	setting.AppSetting.JwtVerificationKeys = verification
+	return setupKeys()
+}
+
+func sign(t *testing.T) string {
+	t.Helper()
+
+	token, err := signToken(jwt.StandardClaims{Subject: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func verify(token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.StandardClaims{}, getVerificationKey)
+	return err
+}
+
+func TestSignTokenRoundTrip(t *testing.T) {
+	for _, alg := range []string{"RS256", "ES256"} {
+		t.Run(alg, func(t *testing.T) {
+			private, _ := writeKeys(t, alg)
+			if err := useKeys(t, alg, private); err != nil {
+				t.Fatal(err)
+			}
+
+			token := sign(t)
+			parsed, _ := jwt.Parse(token, nil)
+			if parsed.Method.Alg() != alg {
+				t.Errorf("alg = %s, want %s", parsed.Method.Alg(), alg)
+			}
+			if kid := parsed.Header["kid"]; kid != signingKey.ID {
+				t.Errorf("kid = %v, want %s", kid, signingKey.ID)
+			}
+			if err := verify(token); err != nil {
+				t.Errorf("verify() = %v, want nil", err)
+			}
+
+			// HS256 tokens are rejected whatever their secret, so a public key cannot be used as one
+			forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{Subject: "alice"}).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := verify(forged); err == nil {
+				t.Error("verify() of an HS256 token = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestSetupKeysRejectsMismatch(t *testing.T) {
+	rsaPrivate, rsaPublic := writeKeys(t, "RS256")
+	ecPrivate, _ := writeKeys(t, "ES256")
+
+	tests := map[string]struct {
+		method, key string
+	}{
+		"RSA key for ES256": {"ES256", rsaPrivate},
+		"EC key for RS256":  {"RS256", ecPrivate},
+		"public key":        {"RS256", rsaPublic},
+		"missing file":      {"RS256", filepath.Join(t.TempDir(), "missing.pem")},
+		"unknown method":    {"PS256", rsaPrivate},
+	}
+	for name, tt := range tests {
+		if err := useKeys(t, tt.method, tt.key); err == nil {
+			t.Errorf("%s: setupKeys() = nil, want an error", name)
+		}
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldPrivate, oldPublic := writeKeys(t, "RS256")
+	newPrivate, _ := writeKeys(t, "ES256")
+
+	if err := useKeys(t, "RS256", oldPrivate); err != nil {
+		t.Fatal(err)
+	}
+	oldToken, oldKid := sign(t), signingKey.ID
+
+	// The new key signs while the retired one keeps verifying what it issued
+	if err := useKeys(t, "ES256", newPrivate, oldPublic); err != nil {
+		t.Fatal(err)
+	}
+	newToken, newKid := sign(t), signingKey.ID
+	if newKid == oldKid {
+		t.Fatalf("kid of the new key = kid of the old key %s", oldKid)
+	}
+	for name, token := range map[string]string{"old": oldToken, "new": newToken} {
+		if err := verify(token); err != nil {
+			t.Errorf("verify() of the %s token = %v, want nil", name, err)
+		}
+	}
+
+	jwks := GetJWKS()
+	if len(jwks) != 2 {
+		t.Fatalf("GetJWKS() = %d keys, want 2", len(jwks))
+	}
+	if jwks[0].Kid != newKid || jwks[0].Kty != "EC" || jwks[0].Alg != "ES256" {
+		t.Errorf("GetJWKS()[0] = %+v, want the ES256 signing key %s", jwks[0], newKid)
+	}
+	if jwks[1].Kid != oldKid || jwks[1].Kty != "RSA" || jwks[1].Alg != "RS256" {
+		t.Errorf("GetJWKS()[1] = %+v, want the RS256 retired key %s", jwks[1], oldKid)
+	}
+
+	// Once the retired key is dropped, its tokens are rejected
+	if err := useKeys(t, "ES256", newPrivate); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(oldToken); err == nil {
+		t.Error("verify() of a token of a dropped key = nil, want an error")
+	}
+	if err := verify(newToken); err != nil {
+		t.Errorf("verify() of the new token = %v, want nil", err)
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// RFC 7638 section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		E:   "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn" +
+			"64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91" +
+			"CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+	if got, want := jwkThumbprint(&jwk), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("jwkThumbprint() = %s, want %s", got, want)
+	}
+}
+
+func TestHS256KeepsJWKSEmpty(t *testing.T) {
+	if err := useKeys(t, "HS256", ""); err != nil {
+		t.Fatal(err)
+	}
+	if jwks := GetJWKS(); len(jwks) != 0 {
+		t.Errorf("GetJWKS() = %d keys, want none for HS256", len(jwks))
+	}
+}
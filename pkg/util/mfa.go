@@ -0,0 +1,37 @@
+package util
+
+import (
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+// GenerateMfaChallenge issue the short-lived token that stands in for a token pair
+// until the second factor of a login is verified
+func GenerateMfaChallenge(username string) (string, error) {
+	token, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	err = jwt_redis_service.StoreMfaChallenge(EncodeSHA256(token), &jwt_redis_service.MfaChallenge{
+		Username:  username,
+		ExpiresAt: time.Now().Add(setting.AppSetting.MfaChallengeExpire).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// GetMfaChallenge look up the challenge a token stands for
+func GetMfaChallenge(token string) (*jwt_redis_service.MfaChallenge, error) {
+	return jwt_redis_service.GetMfaChallenge(EncodeSHA256(token))
+}
+
+// ConsumeMfaChallenge invalidate a challenge token, reporting whether it was still valid
+func ConsumeMfaChallenge(token string) (bool, error) {
+	return jwt_redis_service.ConsumeMfaChallenge(EncodeSHA256(token))
+}
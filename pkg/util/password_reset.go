@@ -0,0 +1,46 @@
+package util
+
+import (
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+// GeneratePasswordResetToken issue the single-use token mailed to a user who forgot their password
+func GeneratePasswordResetToken(username string) (string, error) {
+	token, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	err = jwt_redis_service.StorePasswordReset(EncodeSHA256(token), &jwt_redis_service.PasswordReset{
+		Username:  username,
+		ExpiresAt: time.Now().Add(setting.AppSetting.PasswordResetExpire).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// GetPasswordResetToken look up the user a reset token was issued for, leaving it valid
+func GetPasswordResetToken(token string) (string, error) {
+	r, err := jwt_redis_service.GetPasswordReset(EncodeSHA256(token))
+	if err != nil {
+		return "", err
+	}
+
+	return r.Username, nil
+}
+
+// ConsumePasswordResetToken invalidate a reset token, returning the user it was issued for
+func ConsumePasswordResetToken(token string) (string, error) {
+	r, err := jwt_redis_service.ConsumePasswordReset(EncodeSHA256(token))
+	if err != nil {
+		return "", err
+	}
+
+	return r.Username, nil
+}
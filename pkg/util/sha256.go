@@ -0,0 +1,14 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// EncodeSHA256 sha256 digest
+func EncodeSHA256(value string) string {
+	m := sha256.New()
+	m.Write([]byte(value))
+
+	return hex.EncodeToString(m.Sum(nil))
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 4226 and RFC 6238 test vectors, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 appendix D
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp([]byte("12345678901234567890"), int64(counter)); got != code {
+			t.Errorf("hotp(counter %d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	// RFC 6238 appendix B for SHA-1, cut to the last 6 of the 8 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if !ok {
+			t.Errorf("ValidateTOTP(%s at %d) = false, want true", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / TOTP_PERIOD; step != want {
+			t.Errorf("ValidateTOTP(%s at %d) step = %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// 1111111109 is in step 37037036, whose code is 081804
+	const code, step = "081804", 37037036
+	at := func(s, offset int64) time.Time {
+		return time.Unix(s*TOTP_PERIOD+offset, 0)
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		ok   bool
+	}{
+		{"same step", at(step, 0), true},
+		{"one step later", at(step+1, 0), true},
+		{"end of one step later", at(step+1, TOTP_PERIOD-1), true},
+		{"one step earlier", at(step-1, 0), true},
+		{"two steps later", at(step+2, 0), false},
+		{"two steps earlier", at(step-2, TOTP_PERIOD-1), false},
+	}
+	for _, tt := range tests {
+		got, ok := ValidateTOTP(rfcSecret, code, tt.t)
+		if ok != tt.ok {
+			t.Errorf("%s: ValidateTOTP() = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && got != step {
+			t.Errorf("%s: ValidateTOTP() step = %d, want %d", tt.name, got, step)
+		}
+	}
+}
+
+func TestValidateTOTPInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := map[string]struct {
+		secret, code string
+	}{
+		"lower case secret": {strings.ToLower(rfcSecret), "287082"},
+		"padded secret":     {rfcSecret + "====", "287082"},
+	}
+	for name, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now); !ok {
+			t.Errorf("%s: ValidateTOTP() = false, want true", name)
+		}
+	}
+
+	invalid := map[string]struct {
+		secret, code string
+	}{
+		"wrong code":     {rfcSecret, "287083"},
+		"short code":     {rfcSecret, "28708"},
+		"8 digit code":   {rfcSecret, "94287082"},
+		"empty code":     {rfcSecret, ""},
+		"invalid secret": {"not base32!", "287082"},
+	}
+	for name, tt := range invalid {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now); ok {
+			t.Errorf("%s: ValidateTOTP() = true, want false", name)
+		}
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() = %q, not unpadded base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("GenerateTOTPSecret() key length = %d, want 20", len(key))
+	}
+
+	code := hotp(key, time.Now().Unix()/TOTP_PERIOD)
+	if _, ok := ValidateTOTP(secret, code, time.Now()); !ok {
+		t.Errorf("ValidateTOTP() of the current code of a generated secret = false, want true")
+	}
+}
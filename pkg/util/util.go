@@ -1,8 +1,16 @@
 package util
 
-import "github.com/EDDYCJY/go-gin-example/pkg/setting"
+import (
+	"log"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
 
 // Setup Initialize the util
 func Setup() {
 	jwtSecret = []byte(setting.AppSetting.JwtSecret)
-}
\ No newline at end of file
+
+	if err := setupKeys(); err != nil {
+		log.Fatalf("util.Setup, fail to load JWT keys: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/service/api_key_service"
+	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+)
+
+type AddApiKeyForm struct {
+	Name string `form:"name" valid:"Required;MaxSize(100)"`
+	// Scopes is a comma separated list of permissions, empty for every permission of the role
+	Scopes string `form:"scopes" valid:"MaxSize(255)"`
+	// ExpiresIn is in days, 0 never expires
+	ExpiresIn int `form:"expires_in" valid:"Range(0,3650)"`
+}
+
+// @Summary Create an API key for the current user
+// @Description The key is only returned by this call. Send it as the X-API-Key header instead of a Bearer token.
+// @Produce  json
+// @Param name formData string true "Name"
+// @Param scopes formData string false "Comma separated permissions, e.g. articles:read,articles:write"
+// @Param expires_in formData int false "Days until the key expires, 0 for never"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /auth/api-keys [post]
+func AddApiKey(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form AddApiKeyForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	user, ok := getApiKeyOwner(&appG)
+	if !ok {
+		return
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(form.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope == "" {
+			continue
+		}
+		// A key can never do more than its owner
+		if !rbac.HasPermission(user.Role, scope) {
+			appG.Response(http.StatusBadRequest, e.ERROR_API_KEY_SCOPE_INVALID, nil)
+			return
+		}
+		scopes = append(scopes, scope)
+	}
+
+	apiKeyService := api_key_service.ApiKey{
+		AuthID: user.ID,
+		Name:   form.Name,
+		Scopes: scopes,
+	}
+	if form.ExpiresIn > 0 {
+		apiKeyService.ExpiresOn = int(time.Now().AddDate(0, 0, form.ExpiresIn).Unix())
+	}
+
+	key, err := apiKeyService.Add()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_API_KEY_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"key":        key,
+		"name":       apiKeyService.Name,
+		"scopes":     apiKeyService.Scopes,
+		"expires_on": apiKeyService.ExpiresOn,
+	})
+}
+
+// @Summary List API keys of the current user
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /auth/api-keys [get]
+func GetApiKeys(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	user, ok := getApiKeyOwner(&appG)
+	if !ok {
+		return
+	}
+
+	apiKeyService := api_key_service.ApiKey{AuthID: user.ID}
+	keys, err := apiKeyService.GetAll()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_API_KEYS_FAIL, nil)
+		return
+	}
+
+	lists := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		lists = append(lists, map[string]interface{}{
+			"id":           k.ID,
+			"name":         k.Name,
+			"prefix":       api_key_service.API_KEY_PREFIX + k.Prefix,
+			"scopes":       api_key_service.GetScopes(k),
+			"expires_on":   k.ExpiresOn,
+			"last_used_on": k.LastUsedOn,
+			"created_on":   k.CreatedOn,
+		})
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": lists,
+		"total": len(lists),
+	})
+}
+
+// @Summary Revoke an API key of the current user
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /auth/api-keys/{id} [delete]
+func DeleteApiKey(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	user, ok := getApiKeyOwner(&appG)
+	if !ok {
+		return
+	}
+
+	apiKeyService := api_key_service.ApiKey{ID: id, AuthID: user.ID}
+	exists, err := apiKeyService.ExistByID()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_API_KEY_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_API_KEY, nil)
+		return
+	}
+
+	if err := apiKeyService.Delete(); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_API_KEY_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// getApiKeyOwner loads the account of the token owner, writing the error response on failure
+func getApiKeyOwner(appG *app.Gin) (*models.Auth, bool) {
+	authService := auth_service.Auth{Username: jwt.GetClaims(appG.C).Username}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_USER_FAIL, nil)
+		return nil, false
+	}
+	if user.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_USER, nil)
+		return nil, false
+	}
+
+	return user, true
+}
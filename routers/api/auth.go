@@ -1,16 +1,27 @@
 package api
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/astaxie/beego/validation"
 	"github.com/gin-gonic/gin"
 
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/models"
 	"github.com/EDDYCJY/go-gin-example/pkg/app"
 	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/pwpolicy"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/auth_event_service"
 	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+	"github.com/EDDYCJY/go-gin-example/service/login_guard_service"
 )
 
 type auth struct {
@@ -19,23 +30,58 @@ type auth struct {
 }
 
 // @Summary Login
+// @Description grant_type=password exchanges username/password for a token pair,
+// @Description grant_type=refresh_token rotates a refresh token into a new token pair.
+// @Description Accounts with two-factor authentication get {"mfa_required": true, "mfa_token": "..."} from the
+// @Description password grant instead, to be exchanged with grant_type=mfa, mfa_token and a TOTP or recovery code.
+// @Description grant_type=client_credentials issues an access token to a registered OAuth2 client.
 // @Accept application/x-www-form-urlencoded
 // @Produce  json
-// @Param username formData string true "userName"
-// @Param password formData string true "password"
-// @Param grant_type formData string false "Grant Type" default(password)
-// @Success 200 {object} map[string]interface{} "{"access_token": "jwt_token", "token_type": "Bearer"}"
+// @Param grant_type formData string false "Grant Type" Enums(password, refresh_token, mfa, client_credentials) default(password)
+// @Param username formData string false "userName (password grant)"
+// @Param password formData string false "password (password grant)"
+// @Param refresh_token formData string false "Refresh Token (refresh_token grant)"
+// @Param mfa_token formData string false "MFA Token (mfa grant)"
+// @Param code formData string false "TOTP or recovery code (mfa grant)"
+// @Param client_id formData string false "Client ID (client_credentials grant, or HTTP Basic auth)"
+// @Param client_secret formData string false "Client Secret (client_credentials grant, or HTTP Basic auth)"
+// @Param scope formData string false "Space separated scopes (client_credentials grant)"
+// @Success 200 {object} map[string]interface{} "{"access_token": "jwt_token", "token_type": "Bearer", "expires_in": 900, "refresh_token": "token"}"
 // @Failure 400 {object} app.Response
 // @Failure 401 {object} app.Response
+// @Failure 429 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /auth [post]
 func GetAuth(c *gin.Context) {
 	appG := app.Gin{C: c}
+
+	grantType := c.PostForm("grant_type")
+	if grantType == "" {
+		grantType = c.Query("grant_type")
+	}
+
+	switch grantType {
+	case "", "password":
+		passwordGrant(c)
+	case "refresh_token":
+		refreshTokenGrant(c)
+	case "mfa":
+		mfaGrant(c)
+	case "client_credentials":
+		clientCredentialsGrant(c)
+	default:
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTH_UNSUPPORTED_GRANT_TYPE, nil)
+	}
+}
+
+// passwordGrant issues a token pair for a username/password
+func passwordGrant(c *gin.Context) {
+	appG := app.Gin{C: c}
 	valid := validation.Validation{}
 
 	// Handle both form data and JSON
 	var username, password string
-	
+
 	contentType := c.GetHeader("Content-Type")
 	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
 		username = c.PostForm("username")
@@ -51,7 +97,7 @@ func GetAuth(c *gin.Context) {
 	}
 
 	a := auth{Username: username, Password: password}
-	
+
 	ok, _ := valid.Valid(&a)
 
 	if !ok {
@@ -60,70 +106,379 @@ func GetAuth(c *gin.Context) {
 		return
 	}
 
+	guard := login_guard_service.Guard{Username: username, IP: c.ClientIP()}
+	if lockout := guard.Locked(); lockout > 0 {
+		recordAuthEvent(c, username, auth_event_service.EVENT_LOGIN_FAILURE, "locked")
+		lockedResponse(appG, lockout)
+		return
+	}
+
 	authService := auth_service.Auth{Username: username, Password: password}
 	isExist, err := authService.Check()
+	if err == auth_service.ErrAuthDisabled {
+		recordAuthEvent(c, username, auth_event_service.EVENT_LOGIN_FAILURE, "account_disabled")
+		appG.Response(http.StatusForbidden, e.ERROR_AUTH_DISABLED, nil)
+		return
+	}
 	if err != nil {
 		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_CHECK_TOKEN_FAIL, nil)
 		return
 	}
 
 	if !isExist {
+		recordAuthEvent(c, username, auth_event_service.EVENT_LOGIN_FAILURE, "invalid_credentials")
+		if lockout := guard.Fail(); lockout > 0 {
+			lockedResponse(appG, lockout)
+			return
+		}
 		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH, nil)
 		return
 	}
 
-	token, err := util.GenerateToken(username, password)
+	user, err := authService.Get()
 	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_CHECK_TOKEN_FAIL, nil)
+		return
+	}
+
+	// Failed attempts only reset once the second factor is verified as well
+	if user.TOTPEnabled == 1 {
+		mfaChallengeResponse(appG, username)
+		return
+	}
+	guard.Succeed()
+
+	pair, err := util.GenerateTokenPair(username, user.Role, c.Request.UserAgent(), c.ClientIP())
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	recordAuthEvent(c, username, auth_event_service.EVENT_LOGIN_SUCCESS, "password")
+	tokenResponse(appG, pair)
+}
+
+// refreshTokenGrant rotates a refresh token into a new token pair
+func refreshTokenGrant(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	refreshToken := c.PostForm("refresh_token")
+	if refreshToken == "" {
+		refreshToken = c.Query("refresh_token")
+	}
+	if refreshToken == "" {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	rotated, err := util.RotateRefreshToken(refreshToken)
+	switch err {
+	case nil:
+	case jwt_redis_service.ErrRefreshTokenInvalid:
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_REFRESH_TOKEN_INVALID, nil)
+		return
+	case jwt_redis_service.ErrRefreshTokenReused:
+		logging.Warn("refresh token reuse detected, token family revoked")
+		recordAuthEvent(c, rotated.Username, auth_event_service.EVENT_TOKEN_REVOKED, "refresh_token_reused family="+rotated.Family)
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_REFRESH_TOKEN_REUSED, nil)
+		return
+	case jwt_redis_service.ErrStoreUnavailable:
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	default:
+		logging.Warn(err)
 		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
 		return
 	}
 
+	// Reload the account so role changes apply from the next refresh on
+	authService := auth_service.Auth{Username: rotated.Username}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_CHECK_TOKEN_FAIL, nil)
+		return
+	}
+	if user.ID == 0 {
+		jwt_redis_service.RevokeFamily(rotated.Family)
+		recordAuthEvent(c, rotated.Username, auth_event_service.EVENT_TOKEN_REVOKED, "account_deleted family="+rotated.Family)
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_REFRESH_TOKEN_INVALID, nil)
+		return
+	}
+	if user.Status != models.AUTH_STATUS_ACTIVE {
+		jwt_redis_service.RevokeFamily(rotated.Family)
+		recordAuthEvent(c, rotated.Username, auth_event_service.EVENT_TOKEN_REVOKED, "account_disabled family="+rotated.Family)
+		appG.Response(http.StatusForbidden, e.ERROR_AUTH_DISABLED, nil)
+		return
+	}
+
+	pair, err := util.RenewTokenPair(rotated, user.Role, c.Request.UserAgent(), c.ClientIP())
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	tokenResponse(appG, pair)
+}
+
+// mfaGrant completes the login of a two-factor account with a code for its challenge token
+func mfaGrant(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	mfaToken := c.PostForm("mfa_token")
+	if mfaToken == "" {
+		mfaToken = c.Query("mfa_token")
+	}
+	code := c.PostForm("code")
+	if code == "" {
+		code = c.Query("code")
+	}
+	if mfaToken == "" || code == "" {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	challenge, err := util.GetMfaChallenge(mfaToken)
+	switch err {
+	case nil:
+	case jwt_redis_service.ErrMfaChallengeInvalid:
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_MFA_TOKEN_INVALID, nil)
+		return
+	case jwt_redis_service.ErrStoreUnavailable:
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	default:
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	guard := login_guard_service.Guard{Username: challenge.Username, IP: c.ClientIP()}
+	if lockout := guard.Locked(); lockout > 0 {
+		recordAuthEvent(c, challenge.Username, auth_event_service.EVENT_LOGIN_FAILURE, "locked")
+		lockedResponse(appG, lockout)
+		return
+	}
+
+	authService := auth_service.Auth{Username: challenge.Username}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_CHECK_TOKEN_FAIL, nil)
+		return
+	}
+	if user.ID == 0 || user.TOTPEnabled != 1 {
+		util.ConsumeMfaChallenge(mfaToken)
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_MFA_TOKEN_INVALID, nil)
+		return
+	}
+	if user.Status != models.AUTH_STATUS_ACTIVE {
+		util.ConsumeMfaChallenge(mfaToken)
+		recordAuthEvent(c, user.Username, auth_event_service.EVENT_LOGIN_FAILURE, "account_disabled")
+		appG.Response(http.StatusForbidden, e.ERROR_AUTH_DISABLED, nil)
+		return
+	}
+
+	ok, err := auth_service.VerifySecondFactor(user, code)
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_CHECK_TOKEN_FAIL, nil)
+		return
+	}
+	if !ok {
+		recordAuthEvent(c, user.Username, auth_event_service.EVENT_LOGIN_FAILURE, "mfa_code_invalid")
+		if lockout := guard.Fail(); lockout > 0 {
+			lockedResponse(appG, lockout)
+			return
+		}
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_MFA_CODE_INVALID, nil)
+		return
+	}
+
+	// The challenge is single use, a concurrent request may have redeemed it first
+	consumed, err := util.ConsumeMfaChallenge(mfaToken)
+	if err != nil {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if !consumed {
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_MFA_TOKEN_INVALID, nil)
+		return
+	}
+	guard.Succeed()
+
+	pair, err := util.GenerateTokenPair(user.Username, user.Role, c.Request.UserAgent(), c.ClientIP())
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	recordAuthEvent(c, user.Username, auth_event_service.EVENT_LOGIN_SUCCESS, "mfa")
+	tokenResponse(appG, pair)
+}
+
+// mfaChallengeResponse answers a correct password of a two-factor account with a challenge token
+func mfaChallengeResponse(appG app.Gin, username string) {
+	mfaToken, err := util.GenerateMfaChallenge(username)
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"mfa_required": true,
+		"mfa_token":    mfaToken,
+		"expires_in":   int64(setting.AppSetting.MfaChallengeExpire / time.Second),
+	})
+}
+
+// lockedResponse rejects a login attempt while the username or the client IP is locked out
+func lockedResponse(appG app.Gin, lockout time.Duration) {
+	tooManyRequestsResponse(appG, e.ERROR_AUTH_LOCKED, lockout)
+}
+
+// tooManyRequestsResponse rejects a request with HTTP 429, telling the client when to retry
+func tooManyRequestsResponse(appG app.Gin, code int, wait time.Duration) {
+	retryAfter := int64(math.Ceil(wait.Seconds()))
+	appG.C.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	appG.Response(http.StatusTooManyRequests, code, map[string]interface{}{
+		"retry_after": retryAfter,
+	})
+}
+
+// recordAuthEvent adds an entry for the current request to the audit trail
+func recordAuthEvent(c *gin.Context, username, event, detail string) {
+	authEventService := auth_event_service.AuthEvent{
+		Username:  username,
+		Event:     event,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Detail:    detail,
+	}
+	authEventService.Record()
+}
+
+func tokenResponse(appG app.Gin, pair *util.TokenPair) {
 	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
-		"access_token": token,
-		"token_type": "Bearer",
-		"expires_in": 3600,
+		"access_token":  pair.AccessToken,
+		"token_type":    "Bearer",
+		"expires_in":    pair.ExpiresIn,
+		"refresh_token": pair.RefreshToken,
 	})
 }
 
 // @Summary Logout
 // @Produce  json
-// @Param Authorization header string true "Bearer Token"
 // @Success 200 {object} app.Response
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
+// @Security BearerAuth
 // @Router /auth/logout [post]
 func Logout(c *gin.Context) {
 	appG := app.Gin{C: c}
-	
-	// Get token from Authorization header
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-		appG.Response(http.StatusUnauthorized, e.INVALID_PARAMS, nil)
+	claims := jwt.GetClaims(c)
+
+	// Invalidate the current session and its refresh token family
+	err := util.InvalidateToken(claims)
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
 		return
 	}
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	recordAuthEvent(c, claims.Username, auth_event_service.EVENT_LOGOUT, "jti="+claims.Id)
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
+		"message": "Successfully logged out",
+	})
+}
+
+type RegisterForm struct {
+	Username    string `form:"username" valid:"Required;AlphaDash;MaxSize(50)"`
+	Password    string `form:"password" valid:"Required;MaxSize(50)"`
+	Email       string `form:"email" valid:"Required;Email;MaxSize(100)"`
+	DisplayName string `form:"display_name" valid:"MaxSize(100)"`
+}
+
+// @Summary Register
+// @Description The password has to satisfy the password policy.
+// @Accept application/x-www-form-urlencoded
+// @Produce  json
+// @Param username formData string true "Username"
+// @Param password formData string true "Password"
+// @Param email formData string true "Email"
+// @Param display_name formData string false "DisplayName"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /auth/register [post]
+func Register(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form RegisterForm
+	)
 
-	// Extract token from "Bearer <token>"
-	token := strings.TrimPrefix(authHeader, "Bearer ")
-	if token == "" {
-		appG.Response(http.StatusUnauthorized, e.INVALID_PARAMS, nil)
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
 		return
 	}
 
-	// Parse token to get username
-	claims, err := util.ParseToken(token)
+	authService := auth_service.Auth{
+		Username:    form.Username,
+		Password:    form.Password,
+		Email:       form.Email,
+		DisplayName: form.DisplayName,
+	}
+	exists, err := authService.ExistByUsername()
 	if err != nil {
-		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_CHECK_TOKEN_FAIL, nil)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_USER_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_USER, nil)
 		return
 	}
 
-	// Invalidate token in Redis
-	err = util.InvalidateToken(claims.Username)
+	exists, err = authService.ExistByEmail()
 	if err != nil {
-		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_USER_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_EMAIL, nil)
 		return
 	}
 
-	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
-		"message": "Successfully logged out",
-	})
+	if err := authService.Register(); err != nil {
+		if code, ok := pwpolicy.GetErrorCode(err); ok {
+			appG.Response(http.StatusBadRequest, code, nil)
+			return
+		}
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_USER_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
 }
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/feed"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/service/feed_service"
+)
+
+// @Summary Get the RSS feed of the latest articles
+// @Description RSS 2.0 feed of the latest published articles, open to everyone. Answers 304 when
+// @Description If-None-Match carries the current ETag or If-Modified-Since is not older than Last-Modified.
+// @Produce  xml
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified of the copy held by the client"
+// @Success 200 {string} string "RSS document"
+// @Success 304 {string} string "Not modified"
+// @Failure 500 {object} app.Response
+// @Router /feeds/rss.xml [get]
+func GetRSSFeed(c *gin.Context) {
+	serveFeed(c, feed.FORMAT_RSS)
+}
+
+// @Summary Get the Atom feed of the latest articles
+// @Description Atom 1.0 feed of the latest published articles, open to everyone. Answers 304 when
+// @Description If-None-Match carries the current ETag or If-Modified-Since is not older than Last-Modified.
+// @Produce  xml
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified of the copy held by the client"
+// @Success 200 {string} string "Atom document"
+// @Success 304 {string} string "Not modified"
+// @Failure 500 {object} app.Response
+// @Router /feeds/atom.xml [get]
+func GetAtomFeed(c *gin.Context) {
+	serveFeed(c, feed.FORMAT_ATOM)
+}
+
+// @Summary Get the JSON Feed of the latest articles
+// @Description JSON Feed 1.1 of the latest published articles, open to everyone. Answers 304 when
+// @Description If-None-Match carries the current ETag or If-Modified-Since is not older than Last-Modified.
+// @Produce  json
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified of the copy held by the client"
+// @Success 200 {string} string "JSON Feed document"
+// @Success 304 {string} string "Not modified"
+// @Failure 500 {object} app.Response
+// @Router /feeds/feed.json [get]
+func GetJSONFeed(c *gin.Context) {
+	serveFeed(c, feed.FORMAT_JSON)
+}
+
+// @Summary Get the RSS feed of the latest articles of a tag
+// @Produce  xml
+// @Param id path int true "Tag ID"
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified of the copy held by the client"
+// @Success 200 {string} string "RSS document"
+// @Success 304 {string} string "Not modified"
+// @Failure 400 {object} app.Response
+// @Failure 404 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /feeds/tags/{id}/rss.xml [get]
+func GetTagRSSFeed(c *gin.Context) {
+	serveFeed(c, feed.FORMAT_RSS)
+}
+
+// @Summary Get the Atom feed of the latest articles of a tag
+// @Produce  xml
+// @Param id path int true "Tag ID"
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified of the copy held by the client"
+// @Success 200 {string} string "Atom document"
+// @Success 304 {string} string "Not modified"
+// @Failure 400 {object} app.Response
+// @Failure 404 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /feeds/tags/{id}/atom.xml [get]
+func GetTagAtomFeed(c *gin.Context) {
+	serveFeed(c, feed.FORMAT_ATOM)
+}
+
+// @Summary Get the JSON Feed of the latest articles of a tag
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified of the copy held by the client"
+// @Success 200 {string} string "JSON Feed document"
+// @Success 304 {string} string "Not modified"
+// @Failure 400 {object} app.Response
+// @Failure 404 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /feeds/tags/{id}/feed.json [get]
+func GetTagJSONFeed(c *gin.Context) {
+	serveFeed(c, feed.FORMAT_JSON)
+}
+
+// serveFeed writes the feed in the format, of the tag in the id path parameter if there is one
+func serveFeed(c *gin.Context, format string) {
+	appG := app.Gin{C: c}
+	tagID := 0
+	if id := c.Param("id"); id != "" {
+		tagID = com.StrTo(id).MustInt()
+		valid := validation.Validation{}
+		valid.Min(tagID, 1, "id")
+		if valid.HasErrors() {
+			app.MarkErrors(valid.Errors)
+			appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+			return
+		}
+	}
+
+	feedService := feed_service.Feed{Format: format, TagID: tagID}
+	doc, err := feedService.Get()
+	if err == feed_service.ErrTagNotExist {
+		appG.Response(http.StatusNotFound, e.ERROR_NOT_EXIST_TAG, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_FEED_FAIL, nil)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(setting.AppSetting.FeedCacheExpire/time.Second)))
+	c.Header("ETag", doc.ETag)
+	if !doc.LastModified.IsZero() {
+		c.Header("Last-Modified", doc.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c.Request, doc) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, doc.ContentType, doc.Body)
+}
+
+// notModified checks whether the client holds the current feed. If-None-Match takes precedence
+// over If-Modified-Since, as Last-Modified goes back when the latest article leaves the feed.
+func notModified(r *http.Request, doc *feed_service.Document) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, etag := range strings.Split(match, ",") {
+			etag = strings.TrimSpace(etag)
+			if etag == "*" || strings.TrimPrefix(etag, "W/") == doc.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || doc.LastModified.IsZero() {
+		return false
+	}
+	return !doc.LastModified.After(since)
+}
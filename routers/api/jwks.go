@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+// @Summary Get the JSON Web Key Set
+// @Description Public keys access tokens can be verified with, selected by the kid header of a token.
+// @Description Empty while tokens are signed with HS256.
+// @Produce  json
+// @Success 200 {object} map[string]interface{} "{"keys": [{"kty": "RSA", "kid": "...", "use": "sig", "alg": "RS256", "n": "...", "e": "AQAB"}]}"
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(c *gin.Context) {
+	// Standard JWKS document rather than app.Response, so other services can consume it as is
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"keys": util.GetJWKS(),
+	})
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/auth_event_service"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+	"github.com/EDDYCJY/go-gin-example/service/oauth_client_service"
+)
+
+// clientCredentialsGrant issues an access token to an OAuth2 client (RFC 6749 section 4.4)
+func clientCredentialsGrant(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	client, ok := authenticateClient(c)
+	if !ok {
+		appG.Response(http.StatusUnauthorized, e.ERROR_AUTH_CLIENT_INVALID, nil)
+		return
+	}
+
+	scopes, err := oauth_client_service.GrantScopes(client, c.PostForm("scope"))
+	if err != nil {
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTH_SCOPE_INVALID, nil)
+		return
+	}
+
+	token, err := util.GenerateClientToken(client.ClientID, scopes, c.ClientIP())
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_AUTH_TOKEN, nil)
+		return
+	}
+
+	recordAuthEvent(c, util.GetClientSubject(client.ClientID), auth_event_service.EVENT_LOGIN_SUCCESS, "client_credentials")
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int64(setting.AppSetting.AccessTokenExpire / time.Second),
+		"scope":        strings.Join(scopes, " "),
+	})
+}
+
+// @Summary Introspect a token (RFC 7662)
+// @Description Reports whether an access or refresh token is active and what it was issued for.
+// @Description The caller authenticates as a registered OAuth2 client, with HTTP Basic auth or client_id/client_secret.
+// @Description Responses follow RFC 7662 rather than the usual code/msg/data envelope.
+// @Accept application/x-www-form-urlencoded
+// @Produce  json
+// @Param token formData string true "Token"
+// @Param token_type_hint formData string false "Token type hint" Enums(access_token, refresh_token)
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client Secret"
+// @Success 200 {object} map[string]interface{} "{"active": true, "scope": "articles:read", "client_id": "...", "username": "...", "exp": 1700000000}"
+// @Failure 401 {object} map[string]interface{} "{"error": "invalid_client"}"
+// @Router /oauth/introspect [post]
+func Introspect(c *gin.Context) {
+	if _, ok := authenticateClient(c); !ok {
+		invalidClient(c)
+		return
+	}
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	// Access tokens are JWTs, refresh tokens are opaque
+	var (
+		resp gin.H
+		err  error
+	)
+	if isJWT(token) {
+		resp, err = introspectAccessToken(token)
+	} else {
+		resp, err = introspectRefreshToken(token)
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "temporarily_unavailable"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func introspectAccessToken(token string) (gin.H, error) {
+	claims, err := util.ParseToken(token)
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		return nil, err
+	}
+	if err != nil {
+		return gin.H{"active": false}, nil
+	}
+
+	resp := gin.H{
+		"active":     true,
+		"token_type": "Bearer",
+		"username":   claims.Username,
+		"sub":        claims.Username,
+		"iss":        claims.Issuer,
+		"iat":        claims.IssuedAt,
+		"exp":        claims.ExpiresAt,
+		"jti":        claims.Id,
+	}
+	if claims.ClientID != "" {
+		resp["client_id"] = claims.ClientID
+		resp["scope"] = strings.Join(claims.Scopes, " ")
+	} else {
+		resp["role"] = claims.Role
+	}
+
+	return resp, nil
+}
+
+func introspectRefreshToken(token string) (gin.H, error) {
+	refresh, err := jwt_redis_service.GetActiveRefreshToken(util.EncodeSHA256(token))
+	if err != nil {
+		return nil, err
+	}
+	if refresh == nil {
+		return gin.H{"active": false}, nil
+	}
+
+	return gin.H{
+		"active":     true,
+		"token_type": "refresh_token",
+		"username":   refresh.Username,
+		"sub":        refresh.Username,
+		"exp":        refresh.ExpiresAt,
+	}, nil
+}
+
+// @Summary Revoke a token (RFC 7009)
+// @Description Revokes an access token issued to the calling OAuth2 client. As the RFC requires, unknown
+// @Description tokens and tokens of other clients are answered with 200 as well.
+// @Accept application/x-www-form-urlencoded
+// @Produce  json
+// @Param token formData string true "Token"
+// @Param token_type_hint formData string false "Token type hint" Enums(access_token, refresh_token)
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client Secret"
+// @Success 200 {string} string ""
+// @Failure 401 {object} map[string]interface{} "{"error": "invalid_client"}"
+// @Router /oauth/revoke [post]
+func Revoke(c *gin.Context) {
+	client, ok := authenticateClient(c)
+	if !ok {
+		invalidClient(c)
+		return
+	}
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	// Refresh tokens are never issued to clients, so only access tokens can be theirs
+	if isJWT(token) {
+		claims, err := util.ParseToken(token)
+		if err == jwt_redis_service.ErrStoreUnavailable {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "temporarily_unavailable"})
+			return
+		}
+		if err == nil && claims.ClientID == client.ClientID {
+			if err := util.InvalidateToken(claims); err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "temporarily_unavailable"})
+				return
+			}
+			recordAuthEvent(c, claims.Username, auth_event_service.EVENT_TOKEN_REVOKED, "jti="+claims.Id)
+		}
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// authenticateClient reads client credentials from HTTP Basic auth or the form (RFC 6749 section 2.3.1)
+func authenticateClient(c *gin.Context) (*models.OauthClient, bool) {
+	clientID, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, secret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	client, err := oauth_client_service.Authenticate(clientID, secret)
+	if err != nil {
+		if err != oauth_client_service.ErrClientInvalid {
+			logging.Warn(err)
+		}
+		return nil, false
+	}
+
+	return client, true
+}
+
+func invalidClient(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+}
+
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
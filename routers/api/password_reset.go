@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/pwpolicy"
+	"github.com/EDDYCJY/go-gin-example/service/auth_event_service"
+	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+	"github.com/EDDYCJY/go-gin-example/service/login_guard_service"
+)
+
+type ForgotPasswordForm struct {
+	Email string `form:"email" valid:"Required;Email;MaxSize(100)"`
+}
+
+// @Summary Request a password reset mail
+// @Description Mails a single-use reset link to the account with this email. The response is the same
+// @Description whether or not such an account exists. Too many requests for the email or from the
+// @Description client IP get HTTP 429 with `retry_after` in seconds.
+// @Accept application/x-www-form-urlencoded
+// @Produce  json
+// @Param email formData string true "Email"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 429 {object} app.Response
+// @Router /auth/password/forgot [post]
+func ForgotPassword(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form ForgotPasswordForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	throttle := login_guard_service.ResetThrottle{Email: form.Email, IP: c.ClientIP()}
+	if wait := throttle.Request(); wait > 0 {
+		tooManyRequestsResponse(appG, e.ERROR_AUTH_RESET_THROTTLED, wait)
+		return
+	}
+
+	// Looking up the account and sending the mail happen after the response,
+	// so its timing does not tell whether the email is registered
+	authService := auth_service.Auth{Email: form.Email}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.Error("password reset request panicked:", r)
+			}
+		}()
+
+		if err := authService.RequestPasswordReset(); err != nil {
+			logging.Warn("password reset request failed:", err)
+		}
+	}()
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
+		"message": "If the email belongs to an account, a reset link has been sent to it",
+	})
+}
+
+type ResetPasswordForm struct {
+	Token    string `form:"token" valid:"Required;MaxSize(64)"`
+	Password string `form:"password" valid:"Required;MaxSize(50)"`
+}
+
+// @Summary Reset a password
+// @Description Sets a new password with the token of a reset mail. Every session of the account is revoked.
+// @Description A password rejected by the password policy leaves the token valid for another try.
+// @Accept application/x-www-form-urlencoded
+// @Produce  json
+// @Param token formData string true "Reset token"
+// @Param password formData string true "New password"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 503 {object} app.Response
+// @Router /auth/password/reset [post]
+func ResetPassword(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form ResetPasswordForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	authService := auth_service.Auth{Password: form.Password}
+	err := authService.ResetPassword(form.Token)
+	switch err {
+	case nil:
+	case auth_service.ErrPasswordResetInvalid:
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTH_RESET_TOKEN_INVALID, nil)
+		return
+	case jwt_redis_service.ErrStoreUnavailable:
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	default:
+		if code, ok := pwpolicy.GetErrorCode(err); ok {
+			appG.Response(http.StatusBadRequest, code, nil)
+			return
+		}
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_RESET_PASSWORD_FAIL, nil)
+		return
+	}
+
+	recordAuthEvent(c, authService.Username, auth_event_service.EVENT_PASSWORD_RESET, "")
+	recordAuthEvent(c, authService.Username, auth_event_service.EVENT_TOKEN_REVOKED, "password_reset")
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/auth_event_service"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+// @Summary List sessions of the current user
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /auth/sessions [get]
+func GetSessions(c *gin.Context) {
+	appG := app.Gin{C: c}
+	claims := jwt.GetClaims(c)
+
+	sessions, err := jwt_redis_service.ListSessions(claims.Username)
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_SESSIONS_FAIL, nil)
+		return
+	}
+
+	lists := make([]map[string]interface{}, 0, len(sessions))
+	for _, s := range sessions {
+		lists = append(lists, map[string]interface{}{
+			"id":         s.ID,
+			"device":     s.Device,
+			"ip":         s.IP,
+			"issued_at":  s.IssuedAt,
+			"expires_at": s.ExpiresAt,
+			"current":    s.ID == claims.Id,
+		})
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": lists,
+		"total": len(lists),
+	})
+}
+
+// @Summary Revoke a session of the current user
+// @Produce  json
+// @Param id path string true "Session ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /auth/sessions/{id} [delete]
+func DeleteSession(c *gin.Context) {
+	appG := app.Gin{C: c}
+	claims := jwt.GetClaims(c)
+
+	deleted, err := jwt_redis_service.DeleteSession(claims.Username, c.Param("id"))
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_SESSION_FAIL, nil)
+		return
+	}
+	if !deleted {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_SESSION, nil)
+		return
+	}
+
+	recordAuthEvent(c, claims.Username, auth_event_service.EVENT_TOKEN_REVOKED, "jti="+c.Param("id"))
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Revoke all sessions of the current user except the current one
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /auth/sessions [delete]
+func DeleteOtherSessions(c *gin.Context) {
+	appG := app.Gin{C: c}
+	claims := jwt.GetClaims(c)
+
+	count, err := jwt_redis_service.DeleteOtherSessions(claims.Username, claims.Id)
+	if err == jwt_redis_service.ErrStoreUnavailable {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_SESSION_FAIL, nil)
+		return
+	}
+
+	recordAuthEvent(c, claims.Username, auth_event_service.EVENT_TOKEN_REVOKED, "other_sessions="+strconv.Itoa(count))
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]int{
+		"revoked": count,
+	})
+}
@@ -0,0 +1,209 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+)
+
+// @Summary Get the authors of an article
+// @Description The creator, who is always an owner, and the co-authors with their roles. Needs any role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/authors [get]
+func GetArticleAuthors(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_VIEWER)
+	if !ok {
+		return
+	}
+
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+	authors, err := articleService.GetAuthors()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_AUTHORS_FAIL, nil)
+		return
+	}
+
+	lists := []map[string]interface{}{{
+		"auth_id":  article.CreatedByID,
+		"username": article.CreatedBy,
+		"role":     article_service.ROLE_OWNER,
+		"creator":  true,
+	}}
+	for _, author := range authors {
+		authService := auth_service.Auth{ID: author.AuthID}
+		user, err := authService.Get()
+		if err != nil {
+			appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_AUTHORS_FAIL, nil)
+			return
+		}
+		lists = append(lists, map[string]interface{}{
+			"auth_id":      author.AuthID,
+			"username":     user.Username,
+			"display_name": user.DisplayName,
+			"role":         author.Role,
+			"creator":      false,
+		})
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": lists,
+		"total": len(lists),
+	})
+}
+
+type SetArticleAuthorForm struct {
+	ID     int    `form:"id" valid:"Required;Min(1)"`
+	AuthID int    `form:"auth_id" valid:"Required;Min(1)"`
+	Role   string `form:"role" valid:"Required;MaxSize(20)"`
+}
+
+// @Summary Add a co-author to an article or change their role
+// @Description Needs the owner role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param auth_id path int true "User ID"
+// @Param role formData string true "Role" Enums(owner, editor, viewer)
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/authors/{auth_id} [put]
+func SetArticleAuthor(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = SetArticleAuthorForm{
+			ID:     com.StrTo(c.Param("id")).MustInt(),
+			AuthID: com.StrTo(c.Param("auth_id")).MustInt(),
+		}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+	if !article_service.IsValidRole(form.Role) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_OWNER)
+	if !ok {
+		return
+	}
+
+	authService := auth_service.Auth{ID: form.AuthID}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_USER_FAIL, nil)
+		return
+	}
+	if user.ID == 0 || user.Status != models.AUTH_STATUS_ACTIVE {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_USER, nil)
+		return
+	}
+
+	err = articleService.SetAuthor(user.ID, form.Role, jwt.GetClaims(c).Username)
+	if err == article_service.ErrAuthorIsCreator {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_AUTHOR_CREATOR, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_ARTICLE_AUTHOR_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Remove a co-author from an article
+// @Description Needs the owner role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param auth_id path int true "User ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/authors/{auth_id} [delete]
+func DeleteArticleAuthor(c *gin.Context) {
+	appG := app.Gin{C: c}
+	authID := com.StrTo(c.Param("auth_id")).MustInt()
+	if authID < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_OWNER)
+	if !ok {
+		return
+	}
+
+	removed, err := articleService.RemoveAuthor(authID)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_ARTICLE_AUTHOR_FAIL, nil)
+		return
+	}
+	if !removed {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE_AUTHOR, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// getArticleWithRole checks that the article of the :id param exists and that the current
+// user has the required role on it, writing the error response otherwise
+func getArticleWithRole(appG *app.Gin, required string) (*article_service.Article, bool) {
+	id := com.StrTo(appG.C.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return nil, false
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return nil, false
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return nil, false
+	}
+
+	if !checkArticleRole(appG, &articleService, required) {
+		return nil, false
+	}
+
+	return &articleService, true
+}
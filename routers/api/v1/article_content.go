@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/sanitize"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// checkContentFormat writes an error response if the content format is given and unknown
+func checkContentFormat(appG *app.Gin, format string) bool {
+	if format != "" && !article_service.IsValidContentFormat(format) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return false
+	}
+
+	return true
+}
+
+// respondSaveError writes the response for an article that could not be saved: the reason if its
+// content was rejected as unsafe HTML, errCode otherwise
+func respondSaveError(appG *app.Gin, err error, errCode int) {
+	if unsafe, ok := err.(*sanitize.UnsafeError); ok {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_CONTENT_UNSAFE, map[string]string{
+			"reason": unsafe.Reason,
+		})
+		return
+	}
+
+	logging.Warn(err)
+	appG.Response(http.StatusInternalServerError, errCode, nil)
+}
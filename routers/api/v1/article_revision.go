@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// @Summary Get the revisions of an article
+// @Description Snapshots recorded on every add, edit and restore, latest first. Needs any role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/revisions [get]
+func GetArticleRevisions(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_VIEWER)
+	if !ok {
+		return
+	}
+
+	articleService.PageNum = util.GetPage(c)
+	articleService.PageSize = setting.AppSetting.PageSize
+
+	total, err := articleService.CountRevisions()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_ARTICLE_REVISION_FAIL, nil)
+		return
+	}
+
+	revisions, err := articleService.GetRevisions()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_REVISIONS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": revisions,
+		"total": total,
+	})
+}
+
+// @Summary Get a revision of an article
+// @Description Needs any role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param revision path int true "Revision"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/revisions/{revision} [get]
+func GetArticleRevision(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_VIEWER)
+	if !ok {
+		return
+	}
+
+	rev, ok := getArticleRevision(&appG, articleService, com.StrTo(c.Param("revision")).MustInt())
+	if !ok {
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, rev)
+}
+
+// @Summary Diff two revisions of an article
+// @Description Lists the changed fields and a line-based unified diff of the content between
+// @Description revision from and the given revision. Needs any role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param revision path int true "Revision"
+// @Param from query int false "Revision to compare against, defaults to the previous one"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/revisions/{revision}/diff [get]
+func GetArticleRevisionDiff(c *gin.Context) {
+	appG := app.Gin{C: c}
+	revision := com.StrTo(c.Param("revision")).MustInt()
+	from := revision - 1
+	if arg := c.Query("from"); arg != "" {
+		from = com.StrTo(arg).MustInt()
+		if from < 1 {
+			appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+			return
+		}
+	}
+
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_VIEWER)
+	if !ok {
+		return
+	}
+
+	to, ok := getArticleRevision(&appG, articleService, revision)
+	if !ok {
+		return
+	}
+
+	// The first revision is compared against an empty article
+	fromRev := &models.ArticleRevision{}
+	if from > 0 {
+		if fromRev, ok = getArticleRevision(&appG, articleService, from); !ok {
+			return
+		}
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, article_service.DiffRevisions(fromRev, to))
+}
+
+// @Summary Restore an article to a revision
+// @Description Overwrites the article with the snapshot of the revision, which records a new revision.
+// @Description Needs the editor role on the article, and every tag of the revision must still exist.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param revision path int true "Revision"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/revisions/{revision}/restore [put]
+func RestoreArticleRevision(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_EDITOR)
+	if !ok {
+		return
+	}
+
+	rev, ok := getArticleRevision(&appG, articleService, com.StrTo(c.Param("revision")).MustInt())
+	if !ok {
+		return
+	}
+
+	if !checkTagsExist(&appG, article_service.GetRevisionTagIDs(rev)) {
+		return
+	}
+
+	articleService.ModifiedBy = jwt.GetClaims(c).Username
+	if err := articleService.Restore(rev); err != nil {
+		respondSaveError(&appG, err, e.ERROR_RESTORE_ARTICLE_REVISION_FAIL)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// getArticleRevision loads a revision of the article, writing the error response if it cannot
+func getArticleRevision(appG *app.Gin, articleService *article_service.Article, revision int) (*models.ArticleRevision, bool) {
+	if revision < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return nil, false
+	}
+
+	rev, err := articleService.GetRevision(revision)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_REVISION_FAIL, nil)
+		return nil, false
+	}
+	if rev.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE_REVISION, nil)
+		return nil, false
+	}
+
+	return rev, true
+}
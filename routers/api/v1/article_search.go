@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/search"
+)
+
+// @Summary Search articles
+// @Description Finds the articles containing every keyword in their title, desc or content, best match first.
+// @Description Takes the filters of GET /api/v1/articles. Every result has its relevance as score and highlights:
+// @Description the title and snippets of the desc and content as HTML, the keywords wrapped in <em>.
+// @Produce  json
+// @Param q query string true "Keywords"
+// @Param tag_ids query string false "Comma separated tag IDs"
+// @Param tag_match query string false "Whether articles need any or all of tag_ids" Enums(any, all) default(any)
+// @Param status query string false "Status" Enums(draft, in_review, scheduled, published, archived) default(published)
+// @Param created_by query int false "ID of the user who created the articles"
+// @Param author_id query int false "ID of a user who created or co-authors the articles as owner or editor"
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/search [get]
+func SearchArticles(c *gin.Context) {
+	appG := app.Gin{C: c}
+	valid := validation.Validation{}
+	keywords := c.Query("q")
+	valid.MaxSize(keywords, 100, "q")
+	if len(search.Terms(keywords)) == 0 {
+		valid.SetError("q", "must contain a letter or digit")
+	}
+
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService, ok := getArticleFilters(&appG)
+	if !ok {
+		return
+	}
+
+	results, total, err := articleService.Search(keywords)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_SEARCH_ARTICLES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": results,
+		"total": total,
+	})
+}
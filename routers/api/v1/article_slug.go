@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/slug"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// @Summary Get a single article by its slug
+// @Description Articles that are not published need a role on the article.
+// @Description A former slug of an article redirects to its current slug with 301 Moved Permanently.
+// @Produce  json
+// @Param slug path string true "Slug"
+// @Success 200 {object} app.Response
+// @Success 301 {string} string "Location of the current slug"
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/article-slugs/{slug} [get]
+func GetArticleBySlug(c *gin.Context) {
+	appG := app.Gin{C: c}
+	s := c.Param("slug")
+	if !slug.IsValid(s) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	id, current, err := article_service.ResolveSlug(s)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_RESOLVE_ARTICLE_SLUG_FAIL, nil)
+		return
+	}
+	if current != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/article-slugs/"+url.PathEscape(current))
+		return
+	}
+	if id == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return
+	}
+
+	respondArticle(&appG, &article_service.Article{ID: id})
+}
+
+// checkSlug checks that the slug chosen for an article is well-formed and not used by another
+// article, writing the error response otherwise. An empty slug is generated by the service.
+func checkSlug(appG *app.Gin, articleService *article_service.Article) bool {
+	if articleService.Slug == "" {
+		return true
+	}
+	if !slug.IsValid(articleService.Slug) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return false
+	}
+
+	exists, err := articleService.ExistSlug()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_SLUG_FAIL, nil)
+		return false
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_ARTICLE_SLUG, nil)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+type EditArticleStatusForm struct {
+	ID        int    `form:"id" valid:"Required;Min(1)"`
+	Status    string `form:"status" valid:"Required;MaxSize(20)"`
+	PublishAt int    `form:"publish_at" valid:"Min(0)"`
+}
+
+// @Summary Change the status of an article
+// @Description Moves an article through the publishing workflow. Needs the editor role on the article;
+// @Description moving into or out of scheduled, published or archived also needs articles:publish.
+// @Description Allowed: draft -> in_review, scheduled, published; in_review -> draft, scheduled, published;
+// @Description scheduled -> draft, scheduled, published; published -> draft, archived; archived -> draft, published.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param status formData string true "Status" Enums(draft, in_review, scheduled, published, archived)
+// @Param publish_at formData int false "Publishing time (unix time) of a scheduled article"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 409 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/status [put]
+func EditArticleStatus(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = EditArticleStatusForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+	if !article_service.IsValidStatus(form.Status) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_EDITOR)
+	if !ok {
+		return
+	}
+
+	article, err := articleService.GetUncached()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return
+	}
+
+	claims := jwt.GetClaims(c)
+	if article_service.IsPublishTransition(article.Status, form.Status) && !claims.HasPermission(rbac.PERM_ARTICLES_PUBLISH) {
+		appG.Response(http.StatusForbidden, e.ERROR_AUTH_PERMISSION_DENIED, nil)
+		return
+	}
+
+	articleService.ModifiedBy = claims.Username
+	err = articleService.Transition(article, form.Status, form.PublishAt)
+	if err == article_service.ErrTransitionInvalid {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_TRANSITION_INVALID, nil)
+		return
+	}
+	if err == article_service.ErrPublishAtInvalid {
+		appG.Response(http.StatusBadRequest, e.ERROR_ARTICLE_PUBLISH_AT_INVALID, nil)
+		return
+	}
+	if err == article_service.ErrStatusChanged {
+		appG.Response(http.StatusConflict, e.ERROR_ARTICLE_STATUS_CHANGED, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_ARTICLE_STATUS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"status": form.Status,
+	})
+}
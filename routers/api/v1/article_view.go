@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/view_service"
+)
+
+// POPULAR_MAX_LIMIT bounds the number of popular articles returned at once
+const POPULAR_MAX_LIMIT = 50
+
+// @Summary Get the most viewed articles
+// @Description Published articles ranked by their views today (day), in the last seven days (week) or ever (all),
+// @Description each with its views in the period. Views of the same client within ViewDedupWindow count once.
+// @Produce  json
+// @Param period query string false "Period" Enums(day, week, all) default(week)
+// @Param limit query int false "Number of articles, at most 50" default(10)
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/popular [get]
+func GetPopularArticles(c *gin.Context) {
+	appG := app.Gin{C: c}
+	period := c.DefaultQuery("period", view_service.PERIOD_WEEK)
+	limit := com.StrTo(c.DefaultQuery("limit", "10")).MustInt()
+
+	valid := validation.Validation{}
+	valid.Range(limit, 1, POPULAR_MAX_LIMIT, "limit")
+	if !view_service.IsValidPeriod(period) {
+		valid.SetError("period", "must be day, week or all")
+	}
+	if valid.HasErrors() {
+		app.MarkErrors(valid.Errors)
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	articles, err := article_service.GetPopular(period, limit)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_POPULAR_ARTICLES_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists":  articles,
+		"total":  len(articles),
+		"period": period,
+	})
+}
+
+// viewClient identifies the client viewing an article, for counting its views once per window
+func viewClient(c *gin.Context) string {
+	claims := jwt.GetClaims(c)
+	identity := claims.Username
+	if claims.ClientID != "" {
+		identity = "client:" + claims.ClientID
+	}
+
+	return util.EncodeMD5(identity + "|" + c.ClientIP())
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/auth_event_service"
+)
+
+// @Summary Get auth events
+// @Description Audit trail of logins, logouts, token revocations and password changes, newest first.
+// @Produce  json
+// @Param username query string false "Username, or client:<client_id> for OAuth2 clients"
+// @Param ip query string false "IP"
+// @Param event query string false "Event" Enums(login_success, login_failure, logout, token_revoked, password_change, password_reset)
+// @Param from query int false "From (unix time)"
+// @Param to query int false "To (unix time)"
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/auth-events [get]
+func GetAuthEvents(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	authEventService := auth_event_service.AuthEvent{
+		Username: c.Query("username"),
+		IP:       c.Query("ip"),
+		Event:    c.Query("event"),
+		From:     com.StrTo(c.Query("from")).MustInt(),
+		To:       com.StrTo(c.Query("to")).MustInt(),
+		PageNum:  util.GetPage(c),
+		PageSize: setting.AppSetting.PageSize,
+	}
+	events, err := authEventService.GetAll()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_AUTH_EVENTS_FAIL, nil)
+		return
+	}
+
+	count, err := authEventService.Count()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_AUTH_EVENT_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": events,
+		"total": count,
+	})
+}
+
+// @Summary Export auth events
+// @Description Writes the matching events to an xlsx file under the export path.
+// @Produce  json
+// @Param username formData string false "Username"
+// @Param ip formData string false "IP"
+// @Param event formData string false "Event"
+// @Param from formData int false "From (unix time)"
+// @Param to formData int false "To (unix time)"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/auth-events/export [post]
+func ExportAuthEvents(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	authEventService := auth_event_service.AuthEvent{
+		Username: c.PostForm("username"),
+		IP:       c.PostForm("ip"),
+		Event:    c.PostForm("event"),
+		From:     com.StrTo(c.PostForm("from")).MustInt(),
+		To:       com.StrTo(c.PostForm("to")).MustInt(),
+	}
+	filename, err := authEventService.Export()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXPORT_AUTH_EVENTS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
+		"export_url":      export.GetExcelFullUrl(filename),
+		"export_save_url": export.GetExcelPath() + filename,
+	})
+}
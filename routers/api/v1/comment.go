@@ -0,0 +1,386 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/comment_service"
+)
+
+// @Summary Get the comments of an article
+// @Description A page of threads, oldest first: each top-level comment with its approved replies nested in replies.
+// @Description Deleted comments with replies stay in place without author and content. Articles that are not
+// @Description published need a role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/comments [get]
+func GetArticleComments(c *gin.Context) {
+	appG := app.Gin{C: c}
+	article, ok := getCommentedArticle(&appG, com.StrTo(c.Param("id")).MustInt())
+	if !ok {
+		return
+	}
+	if article.Status != models.ARTICLE_STATUS_PUBLISHED && !checkArticleRole(&appG, &article_service.Article{ID: article.ID}, article_service.ROLE_VIEWER) {
+		return
+	}
+
+	commentService := comment_service.Comment{
+		ArticleID: article.ID,
+		PageNum:   util.GetPage(c),
+		PageSize:  setting.AppSetting.PageSize,
+	}
+	total, err := commentService.CountThreads()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_COMMENT_FAIL, nil)
+		return
+	}
+
+	threads, err := commentService.GetThreads()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_COMMENTS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": threads,
+		"total": total,
+	})
+}
+
+type AddCommentForm struct {
+	ArticleID int    `form:"article_id" valid:"Required;Min(1)"`
+	ParentID  int    `form:"parent_id" valid:"Min(0)"`
+	Content   string `form:"content" valid:"Required;MaxSize(2000)"`
+}
+
+// @Summary Comment on an article
+// @Description Comments wait for moderation, unless the user has comments:moderate or a role on the article.
+// @Description Only published articles with open comments take comments.
+// @Produce  json
+// @Param article_id formData int true "ArticleID"
+// @Param parent_id formData int false "ID of the approved comment replied to"
+// @Param content formData string true "Content, plain text"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/comments [post]
+func AddComment(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form AddCommentForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+	content := strings.TrimSpace(form.Content)
+	if content == "" {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	article, ok := getCommentedArticle(&appG, form.ArticleID)
+	if !ok {
+		return
+	}
+	if article.Status != models.ARTICLE_STATUS_PUBLISHED || article.CommentsClosed == 1 {
+		appG.Response(http.StatusForbidden, e.ERROR_ARTICLE_COMMENTS_CLOSED, nil)
+		return
+	}
+
+	// Comments need a user to belong to, client tokens have none
+	userID, ok := getCurrentUserID(&appG)
+	if !ok {
+		return
+	}
+	if userID == 0 {
+		appG.Response(http.StatusForbidden, e.ERROR_AUTH_PERMISSION_DENIED, nil)
+		return
+	}
+
+	claims := jwt.GetClaims(c)
+	approve := claims.HasPermission(rbac.PERM_COMMENTS_MODERATE)
+	if !approve {
+		role, err := (&article_service.Article{ID: article.ID}).GetRoleOf(userID)
+		if err != nil {
+			appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+			return
+		}
+		approve = role != ""
+	}
+
+	commentService := comment_service.Comment{
+		ArticleID: article.ID,
+		ParentID:  form.ParentID,
+		AuthID:    userID,
+		CreatedBy: claims.Username,
+		Content:   content,
+	}
+	err := commentService.Add(approve)
+	if err == comment_service.ErrParentInvalid {
+		appG.Response(http.StatusBadRequest, e.ERROR_COMMENT_PARENT_INVALID, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_COMMENT_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"id":    commentService.ID,
+		"state": commentService.State,
+	})
+}
+
+// @Summary Close the comments of an article
+// @Description Existing comments stay visible, new ones are refused. Needs the owner role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/comments/close [put]
+func CloseArticleComments(c *gin.Context) {
+	setArticleCommentsClosed(c, true)
+}
+
+// @Summary Open the comments of an article again
+// @Description Needs the owner role on the article.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/articles/{id}/comments/open [put]
+func OpenArticleComments(c *gin.Context) {
+	setArticleCommentsClosed(c, false)
+}
+
+func setArticleCommentsClosed(c *gin.Context, closed bool) {
+	appG := app.Gin{C: c}
+	articleService, ok := getArticleWithRole(&appG, article_service.ROLE_OWNER)
+	if !ok {
+		return
+	}
+
+	if err := articleService.SetCommentsClosed(closed); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_ARTICLE_COMMENTS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"comments_closed": closed,
+	})
+}
+
+// @Summary Get comments to moderate
+// @Description Comments of all articles in a state, latest first.
+// @Produce  json
+// @Param state query string false "State" Enums(pending, approved, spam, deleted) default(pending)
+// @Param article_id query int false "ID of the article the comments belong to"
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/comments [get]
+func GetComments(c *gin.Context) {
+	appG := app.Gin{C: c}
+	state := c.DefaultQuery("state", models.COMMENT_STATE_PENDING)
+	articleID := com.StrTo(c.Query("article_id")).MustInt()
+	if !comment_service.IsValidState(state) || articleID < 0 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	commentService := comment_service.Comment{
+		ArticleID: articleID,
+		State:     state,
+		PageNum:   util.GetPage(c),
+		PageSize:  setting.AppSetting.PageSize,
+	}
+	total, err := commentService.Count()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_COMMENT_FAIL, nil)
+		return
+	}
+
+	comments, err := commentService.GetAll()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_COMMENTS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": comments,
+		"total": total,
+	})
+}
+
+type ModerateCommentsForm struct {
+	IDs   string `form:"ids" valid:"Required;MaxSize(1000)"`
+	State string `form:"state" valid:"Required;MaxSize(20)"`
+}
+
+// @Summary Moderate comments in bulk
+// @Description Approves comments, or rejects them as spam or deleted. Unknown IDs are skipped,
+// @Description changed is the number of comments whose state changed.
+// @Produce  json
+// @Param ids formData string true "Comma separated comment IDs, at most 100"
+// @Param state formData string true "State" Enums(approved, spam, deleted)
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/comments/moderate [put]
+func ModerateComments(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form ModerateCommentsForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	ids, ok := appendIDs(nil, form.IDs)
+	if !ok || len(ids) == 0 || len(ids) > comment_service.MODERATE_MAX_IDS || !comment_service.IsModerationState(form.State) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	changed, err := comment_service.Moderate(ids, form.State, jwt.GetClaims(c).Username)
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_MODERATE_COMMENTS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"changed": changed,
+	})
+}
+
+// @Summary Delete a comment
+// @Description Users delete their own comments, comments:moderate allows deleting any. Replies stay visible.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/comments/{id} [delete]
+func DeleteComment(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	commentService := comment_service.Comment{ID: id}
+	comment, err := commentService.Get()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_COMMENTS_FAIL, nil)
+		return
+	}
+	if comment.ID == 0 || comment.State == models.COMMENT_STATE_DELETED {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_COMMENT, nil)
+		return
+	}
+
+	claims := jwt.GetClaims(c)
+	if !claims.HasPermission(rbac.PERM_COMMENTS_MODERATE) {
+		userID, ok := getCurrentUserID(&appG)
+		if !ok {
+			return
+		}
+		if userID == 0 || userID != comment.AuthID {
+			appG.Response(http.StatusForbidden, e.ERROR_AUTH_PERMISSION_DENIED, nil)
+			return
+		}
+	}
+
+	if err := commentService.Delete(claims.Username); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_COMMENT_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// getCommentedArticle loads a live article, writing the error response if there is none
+func getCommentedArticle(appG *app.Gin, id int) (*models.Article, bool) {
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return nil, false
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistByID()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return nil, false
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_ARTICLE, nil)
+		return nil, false
+	}
+
+	article, err := articleService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_ARTICLE_FAIL, nil)
+		return nil, false
+	}
+
+	return article, true
+}
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/service/oauth_client_service"
+)
+
+type AddOauthClientForm struct {
+	Name   string `form:"name" valid:"Required;MaxSize(100)"`
+	Scopes string `form:"scopes" valid:"Required;MaxSize(255)"`
+}
+
+// @Summary Register an OAuth2 client
+// @Description The client_secret is only returned by this call.
+// @Produce  json
+// @Param name formData string true "Name"
+// @Param scopes formData string true "Comma separated permissions the client may request, e.g. articles:read,tags:read"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/oauth/clients [post]
+func AddOauthClient(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form AddOauthClientForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(form.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope == "" {
+			continue
+		}
+		if !rbac.IsValidPermission(scope) {
+			appG.Response(http.StatusBadRequest, e.ERROR_OAUTH_CLIENT_SCOPE_INVALID, nil)
+			return
+		}
+		scopes = append(scopes, scope)
+	}
+
+	oauthClientService := oauth_client_service.OauthClient{
+		Name:      form.Name,
+		Scopes:    scopes,
+		CreatedBy: jwt.GetClaims(c).Username,
+	}
+	clientID, secret, err := oauthClientService.Add()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_ADD_OAUTH_CLIENT_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"client_id":     clientID,
+		"client_secret": secret,
+		"name":          form.Name,
+		"scopes":        scopes,
+	})
+}
+
+// @Summary Get OAuth2 clients
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/oauth/clients [get]
+func GetOauthClients(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	oauthClientService := oauth_client_service.OauthClient{}
+	clients, err := oauthClientService.GetAll()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_OAUTH_CLIENTS_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": clients,
+		"total": len(clients),
+	})
+}
+
+// @Summary Delete an OAuth2 client
+// @Description Tokens already issued to the client are revoked as well.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/oauth/clients/{id} [delete]
+func DeleteOauthClient(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	oauthClientService := oauth_client_service.OauthClient{ID: id}
+	exists, err := oauthClientService.ExistByID()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_OAUTH_CLIENT_FAIL, nil)
+		return
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_OAUTH_CLIENT, nil)
+		return
+	}
+
+	if err := oauthClientService.Delete(); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_OAUTH_CLIENT_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/astaxie/beego/validation"
 	"github.com/gin-gonic/gin"
 
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
 	"github.com/EDDYCJY/go-gin-example/pkg/app"
 	"github.com/EDDYCJY/go-gin-example/pkg/e"
 	"github.com/EDDYCJY/go-gin-example/pkg/export"
@@ -24,6 +25,7 @@ import (
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/tags [get]
 func GetTags(c *gin.Context) {
 	appG := app.Gin{C: c}
@@ -59,7 +61,6 @@ func GetTags(c *gin.Context) {
 
 type AddTagForm struct {
 	Name      string `form:"name" valid:"Required;MaxSize(100)"`
-	CreatedBy string `form:"created_by" valid:"Required;MaxSize(100)"`
 	State     int    `form:"state" valid:"Range(0,1)"`
 	Description string `form:"description" valid:"MaxSize(255)"` // New test column
 }
@@ -68,11 +69,11 @@ type AddTagForm struct {
 // @Produce  json
 // @Param name formData string true "Name"
 // @Param state formData int false "State"
-// @Param created_by formData string false "CreatedBy"
 // @Success 200 {object} app.Response
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/tags [post]
 func AddTag(c *gin.Context) {
 	var (
@@ -88,7 +89,7 @@ func AddTag(c *gin.Context) {
 
 	tagService := tag_service.Tag{
 		Name:        form.Name,
-		CreatedBy:   form.CreatedBy,
+		CreatedBy:   jwt.GetClaims(c).Username,
 		State:       form.State,
 		Description: form.Description,
 	}
@@ -114,7 +115,6 @@ func AddTag(c *gin.Context) {
 type EditTagForm struct {
 	ID         int    `form:"id" valid:"Required;Min(1)"`
 	Name       string `form:"name" valid:"Required;MaxSize(100)"`
-	ModifiedBy string `form:"modified_by" valid:"Required;MaxSize(100)"`
 	State      int    `form:"state" valid:"Range(0,1)"`
 	Description string `form:"description" valid:"MaxSize(255)"` // New test column
 }
@@ -124,11 +124,11 @@ type EditTagForm struct {
 // @Param id path int true "ID"
 // @Param name formData string true "Name"
 // @Param state formData int false "State"
-// @Param modified_by formData string true "ModifiedBy"
 // @Success 200 {object} app.Response
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/tags/{id} [put]
 func EditTag(c *gin.Context) {
 	var (
@@ -145,7 +145,7 @@ func EditTag(c *gin.Context) {
 	tagService := tag_service.Tag{
 		ID:          form.ID,
 		Name:        form.Name,
-		ModifiedBy:  form.ModifiedBy,
+		ModifiedBy:  jwt.GetClaims(c).Username,
 		State:       form.State,
 		Description: form.Description,
 	}
@@ -177,6 +177,7 @@ func EditTag(c *gin.Context) {
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/tags/{id} [delete]
 func DeleteTag(c *gin.Context) {
 	appG := app.Gin{C: c}
@@ -217,6 +218,7 @@ func DeleteTag(c *gin.Context) {
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/tags/export [post]
 func ExportTag(c *gin.Context) {
 	appG := app.Gin{C: c}
@@ -250,6 +252,7 @@ func ExportTag(c *gin.Context) {
 // @Failure 401 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/tags/import [post]
 func ImportTag(c *gin.Context) {
 	appG := app.Gin{C: c}
@@ -261,7 +264,7 @@ func ImportTag(c *gin.Context) {
 		return
 	}
 
-	tagService := tag_service.Tag{}
+	tagService := tag_service.Tag{CreatedBy: jwt.GetClaims(c).Username}
 	err = tagService.Import(file)
 	if err != nil {
 		logging.Warn(err)
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/gin-gonic/gin"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/qrcode"
+	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+// @Summary Start two-factor authentication enrollment
+// @Description Generates a TOTP secret, returned with its otpauth:// URI and a QR code of it as a PNG data URI.
+// @Description Two-factor authentication stays off until the secret is confirmed with a first code.
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/me/totp [post]
+func EnrollTOTP(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	user, ok := getCurrentUser(&appG)
+	if !ok {
+		return
+	}
+
+	authService := auth_service.Auth{ID: user.ID}
+	enrollment, err := authService.EnrollTOTP()
+	if err == auth_service.ErrTOTPAlreadyEnabled {
+		appG.Response(http.StatusOK, e.ERROR_TOTP_ALREADY_ENABLED, nil)
+		return
+	}
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_TOTP_ENROLL_FAIL, nil)
+		return
+	}
+
+	// The QR code holds the secret, so it is never written to the public qrcode directory
+	qrc := qrcode.NewQrCode(enrollment.URI, 300, 300, qr.M, qr.Auto)
+	qrImage, err := qrc.EncodeDataURI()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_TOTP_ENROLL_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]string{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.URI,
+		"qr_code":          qrImage,
+	})
+}
+
+type TOTPCodeForm struct {
+	Code string `form:"code" valid:"Required;MaxSize(20)"`
+}
+
+// @Summary Confirm two-factor authentication enrollment
+// @Description Enables two-factor authentication and returns the recovery codes, which are only shown once.
+// @Produce  json
+// @Param code formData string true "TOTP code"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/me/totp/confirm [post]
+func ConfirmTOTP(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form TOTPCodeForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	user, ok := getCurrentUser(&appG)
+	if !ok {
+		return
+	}
+
+	authService := auth_service.Auth{ID: user.ID}
+	codes, err := authService.ConfirmTOTP(form.Code)
+	switch err {
+	case nil:
+	case auth_service.ErrTOTPAlreadyEnabled:
+		appG.Response(http.StatusOK, e.ERROR_TOTP_ALREADY_ENABLED, nil)
+		return
+	case auth_service.ErrTOTPNotEnrolled:
+		appG.Response(http.StatusOK, e.ERROR_TOTP_NOT_ENROLLED, nil)
+		return
+	case auth_service.ErrTOTPCodeInvalid:
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTH_MFA_CODE_INVALID, nil)
+		return
+	case jwt_redis_service.ErrStoreUnavailable:
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	default:
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_TOTP_CONFIRM_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"recovery_codes": codes,
+	})
+}
+
+// @Summary Disable two-factor authentication
+// @Produce  json
+// @Param code formData string true "TOTP or recovery code"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/me/totp [delete]
+func DisableTOTP(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form TOTPCodeForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	user, ok := getCurrentUser(&appG)
+	if !ok {
+		return
+	}
+
+	authService := auth_service.Auth{ID: user.ID}
+	err := authService.DisableTOTP(form.Code)
+	switch err {
+	case nil:
+	case auth_service.ErrTOTPNotEnabled:
+		appG.Response(http.StatusOK, e.ERROR_TOTP_NOT_ENABLED, nil)
+		return
+	case auth_service.ErrTOTPCodeInvalid:
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTH_MFA_CODE_INVALID, nil)
+		return
+	case jwt_redis_service.ErrStoreUnavailable:
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	default:
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_TOTP_DISABLE_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
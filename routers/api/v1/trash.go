@@ -0,0 +1,255 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/tag_service"
+)
+
+// @Summary Get deleted articles
+// @Description Articles in the trash, latest deleted first. They are purged after TrashRetentionDays.
+// @Produce  json
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/trash/articles [get]
+func GetTrashedArticles(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService := article_service.Article{
+		PageNum:  util.GetPage(c),
+		PageSize: setting.AppSetting.PageSize,
+	}
+
+	total, err := articleService.CountTrash()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_TRASH_FAIL, nil)
+		return
+	}
+
+	articles, err := articleService.GetTrash()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_TRASH_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": articles,
+		"total": total,
+	})
+}
+
+// @Summary Restore a deleted article
+// @Description The article keeps its status, tags that were deleted in the meantime stay hidden.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/trash/articles/{id}/restore [put]
+func RestoreTrashedArticle(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService, ok := getTrashedArticle(&appG)
+	if !ok {
+		return
+	}
+
+	if err := articleService.Undelete(); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_RESTORE_TRASH_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Permanently delete a deleted article
+// @Description Removes the article with its tags, co-authors, revisions and former slugs. This cannot be undone.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/trash/articles/{id} [delete]
+func PurgeTrashedArticle(c *gin.Context) {
+	appG := app.Gin{C: c}
+	articleService, ok := getTrashedArticle(&appG)
+	if !ok {
+		return
+	}
+
+	if err := articleService.Purge(); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_PURGE_TRASH_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Get deleted tags
+// @Description Tags in the trash, latest deleted first. They are purged after TrashRetentionDays.
+// @Produce  json
+// @Param page query int false "Page"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/trash/tags [get]
+func GetTrashedTags(c *gin.Context) {
+	appG := app.Gin{C: c}
+	tagService := tag_service.Tag{
+		PageNum:  util.GetPage(c),
+		PageSize: setting.AppSetting.PageSize,
+	}
+
+	total, err := tagService.CountTrash()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_TRASH_FAIL, nil)
+		return
+	}
+
+	tags, err := tagService.GetTrash()
+	if err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_TRASH_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": tags,
+		"total": total,
+	})
+}
+
+// @Summary Restore a deleted tag
+// @Description Fails if a live tag took its name in the meantime.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/trash/tags/{id}/restore [put]
+func RestoreTrashedTag(c *gin.Context) {
+	appG := app.Gin{C: c}
+	tagService, ok := getTrashedTag(&appG)
+	if !ok {
+		return
+	}
+
+	exists, err := tagService.ExistByName()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_TAG, nil)
+		return
+	}
+
+	if err := tagService.Undelete(); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_RESTORE_TRASH_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Permanently delete a deleted tag
+// @Description Removes the tag from every article. This cannot be undone.
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/trash/tags/{id} [delete]
+func PurgeTrashedTag(c *gin.Context) {
+	appG := app.Gin{C: c}
+	tagService, ok := getTrashedTag(&appG)
+	if !ok {
+		return
+	}
+
+	if err := tagService.Purge(); err != nil {
+		logging.Warn(err)
+		appG.Response(http.StatusInternalServerError, e.ERROR_PURGE_TRASH_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// getTrashedArticle checks that the article of the :id param is in the trash, writing the error response otherwise
+func getTrashedArticle(appG *app.Gin) (*article_service.Article, bool) {
+	id := com.StrTo(appG.C.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return nil, false
+	}
+
+	articleService := article_service.Article{ID: id}
+	exists, err := articleService.ExistInTrash()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_CHECK_EXIST_ARTICLE_FAIL, nil)
+		return nil, false
+	}
+	if !exists {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TRASHED_ARTICLE, nil)
+		return nil, false
+	}
+
+	return &articleService, true
+}
+
+// getTrashedTag checks that the tag of the :id param is in the trash, writing the error response otherwise
+func getTrashedTag(appG *app.Gin) (*tag_service.Tag, bool) {
+	id := com.StrTo(appG.C.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return nil, false
+	}
+
+	tagService := tag_service.Tag{ID: id}
+	tag, err := tagService.GetFromTrash()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_TAG_FAIL, nil)
+		return nil, false
+	}
+	if tag.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_TRASHED_TAG, nil)
+		return nil, false
+	}
+
+	tagService.Name = tag.Name
+	return &tagService, true
+}
@@ -0,0 +1,404 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/app"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/pwpolicy"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/auth_event_service"
+	"github.com/EDDYCJY/go-gin-example/service/auth_service"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+	"github.com/EDDYCJY/go-gin-example/service/login_guard_service"
+)
+
+// @Summary Get the current user
+// @Produce  json
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/me [get]
+func GetMe(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	user, ok := getCurrentUser(&appG)
+	if !ok {
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, user)
+}
+
+type EditMeForm struct {
+	Email       string `form:"email" valid:"Required;Email;MaxSize(100)"`
+	DisplayName string `form:"display_name" valid:"MaxSize(100)"`
+}
+
+// @Summary Update the current user's profile
+// @Produce  json
+// @Param email formData string true "Email"
+// @Param display_name formData string false "DisplayName"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/me [put]
+func EditMe(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form EditMeForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	user, ok := getCurrentUser(&appG)
+	if !ok {
+		return
+	}
+
+	authService := auth_service.Auth{
+		ID:          user.ID,
+		Email:       form.Email,
+		DisplayName: form.DisplayName,
+	}
+	exists, err := authService.ExistByEmail()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EXIST_USER_FAIL, nil)
+		return
+	}
+	if exists {
+		appG.Response(http.StatusOK, e.ERROR_EXIST_EMAIL, nil)
+		return
+	}
+
+	if err := authService.EditProfile(); err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_USER_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+type ChangePasswordForm struct {
+	OldPassword string `form:"old_password" valid:"Required;MaxSize(50)"`
+	NewPassword string `form:"new_password" valid:"Required;MaxSize(50)"`
+}
+
+// @Summary Change the current user's password
+// @Description The new password has to satisfy the password policy. Every other session of the user is revoked on success.
+// @Produce  json
+// @Param old_password formData string true "OldPassword"
+// @Param new_password formData string true "NewPassword"
+// @Success 200 {object} app.Response
+// @Failure 400 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Router /api/v1/me/password [put]
+func ChangePassword(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form ChangePasswordForm
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	user, ok := getCurrentUser(&appG)
+	if !ok {
+		return
+	}
+
+	authService := auth_service.Auth{ID: user.ID, Password: form.OldPassword}
+	valid, err := authService.CheckPassword()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_USER_FAIL, nil)
+		return
+	}
+	if !valid {
+		appG.Response(http.StatusBadRequest, e.ERROR_AUTH_OLD_PASSWORD, nil)
+		return
+	}
+
+	authService.Password = form.NewPassword
+	if err := authService.ChangePassword(); err != nil {
+		if code, ok := pwpolicy.GetErrorCode(err); ok {
+			appG.Response(http.StatusBadRequest, code, nil)
+			return
+		}
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_USER_FAIL, nil)
+		return
+	}
+
+	// Sign out everywhere else, the current session stays usable
+	claims := jwt.GetClaims(c)
+	if _, err := jwt_redis_service.DeleteOtherSessions(claims.Username, claims.Id); err != nil {
+		appG.Response(http.StatusServiceUnavailable, e.ERROR_AUTH_SESSION_STORE_UNAVAILABLE, nil)
+		return
+	}
+
+	recordAuthEvent(c, user.Username, auth_event_service.EVENT_PASSWORD_CHANGE, "")
+	recordAuthEvent(c, user.Username, auth_event_service.EVENT_TOKEN_REVOKED, "password_change")
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Get multiple users
+// @Produce  json
+// @Param role query string false "Role"
+// @Param status query int false "Status"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/users [get]
+func GetUsers(c *gin.Context) {
+	appG := app.Gin{C: c}
+	status := -1
+	if arg := c.Query("status"); arg != "" {
+		status = com.StrTo(arg).MustInt()
+	}
+
+	authService := auth_service.Auth{
+		Role:     c.Query("role"),
+		Status:   status,
+		PageNum:  util.GetPage(c),
+		PageSize: setting.AppSetting.PageSize,
+	}
+	users, err := authService.GetAll()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_USERS_FAIL, nil)
+		return
+	}
+
+	count, err := authService.Count()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_COUNT_USER_FAIL, nil)
+		return
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, map[string]interface{}{
+		"lists": users,
+		"total": count,
+	})
+}
+
+type EditUserForm struct {
+	ID   int    `form:"id" valid:"Required;Min(1)"`
+	Role string `form:"role" valid:"MaxSize(20)"`
+	// Status -1 leaves the status unchanged
+	Status int `form:"status" valid:"Range(-1,1)"`
+}
+
+// @Summary Update a user's role or status
+// @Description Setting the role or disabling the user revokes all of its sessions.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param role formData string false "Role" Enums(admin, editor, author, reader)
+// @Param status formData int false "Status" Enums(0, 1)
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/users/{id} [put]
+func EditUser(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = EditUserForm{ID: com.StrTo(c.Param("id")).MustInt(), Status: -1}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+	if form.Role != "" && !rbac.IsValidRole(form.Role) {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	authService := auth_service.Auth{ID: form.ID, Role: form.Role, Status: form.Status}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_USER_FAIL, nil)
+		return
+	}
+	if user.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_USER, nil)
+		return
+	}
+
+	// An administrator locking themselves out could leave nobody to undo it
+	if isCurrentUser(c, user) &&
+		(form.Status == models.AUTH_STATUS_DISABLED || (form.Role != "" && form.Role != rbac.ROLE_ADMIN)) {
+		appG.Response(http.StatusBadRequest, e.ERROR_EDIT_SELF_USER_FAIL, nil)
+		return
+	}
+
+	if err := authService.Edit(); err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_EDIT_USER_FAIL, nil)
+		return
+	}
+	if authService.RevokesSessions() {
+		recordAuthEvent(c, user.Username, auth_event_service.EVENT_TOKEN_REVOKED, "user_edit by="+jwt.GetClaims(c).Username)
+	}
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// @Summary Delete a user
+// @Produce  json
+// @Param id path int true "ID"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/users/{id} [delete]
+func DeleteUser(c *gin.Context) {
+	appG := app.Gin{C: c}
+	id := com.StrTo(c.Param("id")).MustInt()
+	if id < 1 {
+		appG.Response(http.StatusBadRequest, e.INVALID_PARAMS, nil)
+		return
+	}
+
+	authService := auth_service.Auth{ID: id}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_USER_FAIL, nil)
+		return
+	}
+	if user.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_USER, nil)
+		return
+	}
+	if isCurrentUser(c, user) {
+		appG.Response(http.StatusBadRequest, e.ERROR_EDIT_SELF_USER_FAIL, nil)
+		return
+	}
+
+	if err := authService.Delete(); err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_DELETE_USER_FAIL, nil)
+		return
+	}
+
+	recordAuthEvent(c, user.Username, auth_event_service.EVENT_TOKEN_REVOKED, "user_delete by="+jwt.GetClaims(c).Username)
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// getCurrentUser loads the account of the token owner, writing the error response on failure
+func getCurrentUser(appG *app.Gin) (*models.Auth, bool) {
+	claims := jwt.GetClaims(appG.C)
+	authService := auth_service.Auth{Username: claims.Username}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_GET_USER_FAIL, nil)
+		return nil, false
+	}
+	if user.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_USER, nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// getCurrentUserID returns the account ID of the token owner, 0 for OAuth2 client tokens
+// which do not belong to an account
+func getCurrentUserID(appG *app.Gin) (int, bool) {
+	if jwt.GetClaims(appG.C).ClientID != "" {
+		return 0, true
+	}
+
+	user, ok := getCurrentUser(appG)
+	if !ok {
+		return 0, false
+	}
+
+	return user.ID, true
+}
+
+func isCurrentUser(c *gin.Context, user *models.Auth) bool {
+	return jwt.GetClaims(c).Username == user.Username
+}
+
+type UnlockUserForm struct {
+	ID int    `form:"id" valid:"Required;Min(1)"`
+	IP string `form:"ip" valid:"MaxSize(45)"`
+}
+
+// @Summary Lift a user's login lockout
+// @Description Resets the failed login attempts of the user, and of the client IP if given.
+// @Produce  json
+// @Param id path int true "ID"
+// @Param ip formData string false "IP"
+// @Success 200 {object} app.Response
+// @Failure 401 {object} app.Response
+// @Failure 403 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/users/{id}/unlock [post]
+func UnlockUser(c *gin.Context) {
+	var (
+		appG = app.Gin{C: c}
+		form = UnlockUserForm{ID: com.StrTo(c.Param("id")).MustInt()}
+	)
+
+	httpCode, errCode := app.BindAndValid(c, &form)
+	if errCode != e.SUCCESS {
+		appG.Response(httpCode, errCode, nil)
+		return
+	}
+
+	authService := auth_service.Auth{ID: form.ID}
+	user, err := authService.Get()
+	if err != nil {
+		appG.Response(http.StatusInternalServerError, e.ERROR_UNLOCK_USER_FAIL, nil)
+		return
+	}
+	if user.ID == 0 {
+		appG.Response(http.StatusOK, e.ERROR_NOT_EXIST_USER, nil)
+		return
+	}
+
+	guard := login_guard_service.Guard{Username: user.Username, IP: form.IP}
+	guard.Unlock()
+
+	appG.Response(http.StatusOK, e.SUCCESS, nil)
+}
+
+// recordAuthEvent adds an entry for the current request to the audit trail
+func recordAuthEvent(c *gin.Context, username, event, detail string) {
+	authEventService := auth_event_service.AuthEvent{
+		Username:  username,
+		Event:     event,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Detail:    detail,
+	}
+	authEventService.Record()
+}
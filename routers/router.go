@@ -10,8 +10,10 @@ import (
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 
 	"github.com/EDDYCJY/go-gin-example/middleware/jwt"
+	"github.com/EDDYCJY/go-gin-example/middleware/permission"
 	"github.com/EDDYCJY/go-gin-example/pkg/export"
 	"github.com/EDDYCJY/go-gin-example/pkg/qrcode"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
 	"github.com/EDDYCJY/go-gin-example/pkg/upload"
 	"github.com/EDDYCJY/go-gin-example/routers/api"
 	"github.com/EDDYCJY/go-gin-example/routers/api/v1"
@@ -28,38 +30,148 @@ func InitRouter() *gin.Engine {
 	r.StaticFS("/qrcode", http.Dir(qrcode.GetQrCodeFullPath()))
 
 	r.POST("/auth", api.GetAuth)
-	r.POST("/auth/logout", api.Logout)
+	r.POST("/auth/register", api.Register)
+	r.POST("/auth/password/forgot", api.ForgotPassword)
+	r.POST("/auth/password/reset", api.ResetPassword)
+	r.GET("/.well-known/jwks.json", api.GetJWKS)
+	//订阅已发布文章，无需登录
+	r.GET("/feeds/rss.xml", api.GetRSSFeed)
+	r.GET("/feeds/atom.xml", api.GetAtomFeed)
+	r.GET("/feeds/feed.json", api.GetJSONFeed)
+	//订阅指定标签下的已发布文章
+	r.GET("/feeds/tags/:id/rss.xml", api.GetTagRSSFeed)
+	r.GET("/feeds/tags/:id/atom.xml", api.GetTagAtomFeed)
+	r.GET("/feeds/tags/:id/feed.json", api.GetTagJSONFeed)
+	r.POST("/oauth/introspect", api.Introspect)
+	r.POST("/oauth/revoke", api.Revoke)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.POST("/upload", api.UploadImage)
 
+	auth := r.Group("/auth")
+	auth.Use(jwt.JWT(), jwt.SessionOnly())
+	{
+		//退出登录
+		auth.POST("/logout", api.Logout)
+		//获取当前用户的会话列表
+		auth.GET("/sessions", api.GetSessions)
+		//注销除当前会话外的所有会话
+		auth.DELETE("/sessions", api.DeleteOtherSessions)
+		//注销指定会话
+		auth.DELETE("/sessions/:id", api.DeleteSession)
+		//创建API密钥
+		auth.POST("/api-keys", api.AddApiKey)
+		//获取当前用户的API密钥列表
+		auth.GET("/api-keys", api.GetApiKeys)
+		//吊销指定API密钥
+		auth.DELETE("/api-keys/:id", api.DeleteApiKey)
+	}
+
 	apiv1 := r.Group("/api/v1")
 	apiv1.Use(jwt.JWT())
 	{
+		//获取当前用户信息
+		apiv1.GET("/me", v1.GetMe)
+		//更新当前用户信息
+		apiv1.PUT("/me", jwt.SessionOnly(), v1.EditMe)
+		//修改当前用户密码
+		apiv1.PUT("/me/password", jwt.SessionOnly(), v1.ChangePassword)
+		//开始绑定两步验证
+		apiv1.POST("/me/totp", jwt.SessionOnly(), v1.EnrollTOTP)
+		//确认绑定两步验证
+		apiv1.POST("/me/totp/confirm", jwt.SessionOnly(), v1.ConfirmTOTP)
+		//关闭两步验证
+		apiv1.DELETE("/me/totp", jwt.SessionOnly(), v1.DisableTOTP)
+
+		//获取用户列表
+		apiv1.GET("/users", permission.Require(rbac.PERM_USERS_READ), v1.GetUsers)
+		//更新指定用户的角色或状态
+		apiv1.PUT("/users/:id", permission.Require(rbac.PERM_USERS_WRITE), v1.EditUser)
+		//删除指定用户
+		apiv1.DELETE("/users/:id", permission.Require(rbac.PERM_USERS_DELETE), v1.DeleteUser)
+		//解除指定用户的登录锁定
+		apiv1.POST("/users/:id/unlock", permission.Require(rbac.PERM_USERS_WRITE), v1.UnlockUser)
+
+		//获取认证审计日志
+		apiv1.GET("/auth-events", permission.Require(rbac.PERM_AUDIT_READ), v1.GetAuthEvents)
+		//导出认证审计日志
+		apiv1.POST("/auth-events/export", permission.Require(rbac.PERM_AUDIT_READ), v1.ExportAuthEvents)
+
+		//获取OAuth2客户端列表
+		apiv1.GET("/oauth/clients", jwt.SessionOnly(), permission.Require(rbac.PERM_OAUTH_CLIENTS_MANAGE), v1.GetOauthClients)
+		//注册OAuth2客户端
+		apiv1.POST("/oauth/clients", jwt.SessionOnly(), permission.Require(rbac.PERM_OAUTH_CLIENTS_MANAGE), v1.AddOauthClient)
+		//删除指定OAuth2客户端
+		apiv1.DELETE("/oauth/clients/:id", jwt.SessionOnly(), permission.Require(rbac.PERM_OAUTH_CLIENTS_MANAGE), v1.DeleteOauthClient)
+
 		//获取标签列表
-		apiv1.GET("/tags", v1.GetTags)
+		apiv1.GET("/tags", permission.Require(rbac.PERM_TAGS_READ), v1.GetTags)
 		//新建标签
-		apiv1.POST("/tags", v1.AddTag)
+		apiv1.POST("/tags", permission.Require(rbac.PERM_TAGS_WRITE), v1.AddTag)
 		//更新指定标签
-		apiv1.PUT("/tags/:id", v1.EditTag)
+		apiv1.PUT("/tags/:id", permission.Require(rbac.PERM_TAGS_WRITE), v1.EditTag)
 		//删除指定标签
-		apiv1.DELETE("/tags/:id", v1.DeleteTag)
+		apiv1.DELETE("/tags/:id", permission.Require(rbac.PERM_TAGS_DELETE), v1.DeleteTag)
 		//导出标签
-		apiv1.POST("/tags/export", v1.ExportTag)
+		apiv1.POST("/tags/export", permission.Require(rbac.PERM_TAGS_READ), v1.ExportTag)
 		//导入标签
-		apiv1.POST("/tags/import", v1.ImportTag)
+		apiv1.POST("/tags/import", permission.Require(rbac.PERM_TAGS_WRITE), v1.ImportTag)
 
 		//获取文章列表
-		apiv1.GET("/articles", v1.GetArticles)
-		//获取指定文章
-		apiv1.GET("/articles/:id", v1.GetArticle)
+		apiv1.GET("/articles", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticles)
+		//获取指定文章，/articles/search 与 /articles/popular 由 GetArticle 转给 SearchArticles 与 GetPopularArticles
+		apiv1.GET("/articles/:id", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticle)
+		//通过链接别名获取文章
+		apiv1.GET("/article-slugs/:slug", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticleBySlug)
 		//新建文章
-		apiv1.POST("/articles", v1.AddArticle)
+		apiv1.POST("/articles", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.AddArticle)
 		//更新指定文章
-		apiv1.PUT("/articles/:id", v1.EditArticle)
+		apiv1.PUT("/articles/:id", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.EditArticle)
 		//删除指定文章
-		apiv1.DELETE("/articles/:id", v1.DeleteArticle)
+		apiv1.DELETE("/articles/:id", permission.Require(rbac.PERM_ARTICLES_DELETE), v1.DeleteArticle)
+		//修改文章发布状态
+		apiv1.PUT("/articles/:id/status", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.EditArticleStatus)
+		//获取文章作者
+		apiv1.GET("/articles/:id/authors", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticleAuthors)
+		//添加文章协作者或修改其权限
+		apiv1.PUT("/articles/:id/authors/:auth_id", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.SetArticleAuthor)
+		//移除文章协作者
+		apiv1.DELETE("/articles/:id/authors/:auth_id", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.DeleteArticleAuthor)
+		//获取文章历史版本
+		apiv1.GET("/articles/:id/revisions", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticleRevisions)
+		//获取文章指定版本
+		apiv1.GET("/articles/:id/revisions/:revision", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticleRevision)
+		//对比文章版本
+		apiv1.GET("/articles/:id/revisions/:revision/diff", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticleRevisionDiff)
+		//恢复文章到指定版本
+		apiv1.PUT("/articles/:id/revisions/:revision/restore", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.RestoreArticleRevision)
+		//获取文章评论
+		apiv1.GET("/articles/:id/comments", permission.Require(rbac.PERM_ARTICLES_READ), v1.GetArticleComments)
+		//关闭文章评论
+		apiv1.PUT("/articles/:id/comments/close", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.CloseArticleComments)
+		//重新开放文章评论
+		apiv1.PUT("/articles/:id/comments/open", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.OpenArticleComments)
 		//生成文章海报
-		apiv1.POST("/articles/poster/generate", v1.GenerateArticlePoster)
+		apiv1.POST("/articles/poster/generate", permission.Require(rbac.PERM_ARTICLES_WRITE), v1.GenerateArticlePoster)
+		//发表文章评论或回复，gin 无法在 /articles/poster 旁注册 POST /articles/:id/comments
+		apiv1.POST("/comments", permission.Require(rbac.PERM_COMMENTS_WRITE), v1.AddComment)
+		//获取待审核等状态的评论
+		apiv1.GET("/comments", permission.Require(rbac.PERM_COMMENTS_MODERATE), v1.GetComments)
+		//批量审核评论
+		apiv1.PUT("/comments/moderate", permission.Require(rbac.PERM_COMMENTS_MODERATE), v1.ModerateComments)
+		//删除指定评论
+		apiv1.DELETE("/comments/:id", permission.Require(rbac.PERM_COMMENTS_WRITE), v1.DeleteComment)
+		//获取回收站中的文章
+		apiv1.GET("/trash/articles", permission.Require(rbac.PERM_ARTICLES_MANAGE), v1.GetTrashedArticles)
+		//恢复回收站中的文章
+		apiv1.PUT("/trash/articles/:id/restore", permission.Require(rbac.PERM_ARTICLES_MANAGE), v1.RestoreTrashedArticle)
+		//彻底删除回收站中的文章
+		apiv1.DELETE("/trash/articles/:id", permission.Require(rbac.PERM_ARTICLES_MANAGE), v1.PurgeTrashedArticle)
+		//获取回收站中的标签
+		apiv1.GET("/trash/tags", permission.Require(rbac.PERM_TAGS_DELETE), v1.GetTrashedTags)
+		//恢复回收站中的标签
+		apiv1.PUT("/trash/tags/:id/restore", permission.Require(rbac.PERM_TAGS_DELETE), v1.RestoreTrashedTag)
+		//彻底删除回收站中的标签
+		apiv1.DELETE("/trash/tags/:id", permission.Require(rbac.PERM_TAGS_DELETE), v1.PurgeTrashedTag)
 	}
 
 	return r
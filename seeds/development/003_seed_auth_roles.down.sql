@@ -0,0 +1,2 @@
+-- Rollback development seed data for auth roles
+UPDATE `blog_auth` SET `role` = 'reader' WHERE `username` IN ('admin', 'testuser', 'developer');
\ No newline at end of file
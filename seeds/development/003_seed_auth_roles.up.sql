@@ -0,0 +1,4 @@
+-- Development seed data for auth roles
+UPDATE `blog_auth` SET `role` = 'admin' WHERE `username` = 'admin';
+UPDATE `blog_auth` SET `role` = 'author' WHERE `username` = 'testuser';
+UPDATE `blog_auth` SET `role` = 'editor' WHERE `username` = 'developer';
\ No newline at end of file
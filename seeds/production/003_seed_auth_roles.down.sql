@@ -0,0 +1,2 @@
+-- Rollback production seed data for auth roles
+UPDATE `blog_auth` SET `role` = 'reader' WHERE `username` = 'admin';
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Production seed data for auth roles
+UPDATE `blog_auth` SET `role` = 'admin' WHERE `username` = 'admin';
\ No newline at end of file
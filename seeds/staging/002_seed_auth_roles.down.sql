@@ -0,0 +1,2 @@
+-- Rollback staging seed data for auth roles
+UPDATE `blog_auth` SET `role` = 'reader' WHERE `username` IN ('admin', 'staging_user');
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Staging seed data for auth roles
+UPDATE `blog_auth` SET `role` = 'admin' WHERE `username` = 'admin';
+UPDATE `blog_auth` SET `role` = 'author' WHERE `username` = 'staging_user';
\ No newline at end of file
@@ -0,0 +1,123 @@
+package api_key_service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+const (
+	// API_KEY_PREFIX marks a string as one of our API keys, e.g. for secret scanners
+	API_KEY_PREFIX = "gbk_"
+	// API_KEY_TOUCH_INTERVAL is how often, in seconds, last_used_on is written for a busy key
+	API_KEY_TOUCH_INTERVAL = 60
+)
+
+var (
+	ErrApiKeyInvalid = errors.New("api key is invalid")
+	ErrApiKeyExpired = errors.New("api key has expired")
+)
+
+type ApiKey struct {
+	ID        int
+	AuthID    int
+	Name      string
+	Scopes    []string
+	ExpiresOn int
+}
+
+// Add creates the key and returns it in plain text, which is the only time it is available
+func (a *ApiKey) Add() (string, error) {
+	prefix, err := randomHex(4)
+	if err != nil {
+		return "", err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return "", err
+	}
+
+	key := API_KEY_PREFIX + prefix + "_" + secret
+	err = models.AddApiKey(map[string]interface{}{
+		"auth_id":    a.AuthID,
+		"name":       a.Name,
+		"prefix":     prefix,
+		"key_hash":   util.EncodeSHA256(key),
+		"scopes":     strings.Join(a.Scopes, ","),
+		"expires_on": a.ExpiresOn,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (a *ApiKey) GetAll() ([]*models.ApiKey, error) {
+	return models.GetApiKeys(a.AuthID)
+}
+
+func (a *ApiKey) ExistByID() (bool, error) {
+	return models.ExistApiKeyByID(a.ID, a.AuthID)
+}
+
+func (a *ApiKey) Delete() error {
+	return models.DeleteApiKey(a.ID, a.AuthID)
+}
+
+// Authenticate looks up the key a client presented and records its use
+func Authenticate(key string) (*models.ApiKey, error) {
+	parts := strings.Split(strings.TrimPrefix(key, API_KEY_PREFIX), "_")
+	if !strings.HasPrefix(key, API_KEY_PREFIX) || len(parts) != 2 {
+		return nil, ErrApiKeyInvalid
+	}
+
+	apiKey, err := models.GetApiKeyByPrefix(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.ID == 0 {
+		return nil, ErrApiKeyInvalid
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(util.EncodeSHA256(key))) != 1 {
+		return nil, ErrApiKeyInvalid
+	}
+
+	now := int(time.Now().Unix())
+	if apiKey.ExpiresOn > 0 && apiKey.ExpiresOn <= now {
+		return nil, ErrApiKeyExpired
+	}
+
+	if now-apiKey.LastUsedOn >= API_KEY_TOUCH_INTERVAL {
+		if err := models.TouchApiKey(apiKey.ID, now); err != nil {
+			logging.Warn("api key last used update failed:", err)
+		}
+	}
+
+	return apiKey, nil
+}
+
+// GetScopes splits the stored scopes of a key, empty meaning every permission of the owner's role
+func GetScopes(apiKey *models.ApiKey) []string {
+	if apiKey.Scopes == "" {
+		return nil
+	}
+
+	return strings.Split(apiKey.Scopes, ",")
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -3,55 +3,105 @@ package article_service
 import (
 	"encoding/json"
 
+	"github.com/jinzhu/gorm"
+
 	"github.com/EDDYCJY/go-gin-example/models"
 	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
 	"github.com/EDDYCJY/go-gin-example/pkg/logging"
 	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+	"github.com/EDDYCJY/go-gin-example/service/search_service"
 )
 
 type Article struct {
 	ID            int
-	TagID         int
+	TagIDs        []int
 	Title         string
+	Slug          string
 	Desc          string
 	Content       string
+	ContentFormat string
+	ContentHtml   string
+	ContentText   string
 	CoverImageUrl string
-	State         int
+	Status        string
 	CreatedBy     string
+	CreatedByID   int
 	ModifiedBy    string
 
+	// AuthorID lists the articles a user created or co-authors as owner or editor
+	AuthorID int
+	// TagMatchAll lists the articles carrying every one of TagIDs instead of any
+	TagMatchAll bool
+
 	PageNum  int
 	PageSize int
 }
 
 func (a *Article) Add() error {
+	if err := a.render(); err != nil {
+		return err
+	}
+	if a.Slug == "" {
+		s, err := a.newSlug()
+		if err != nil {
+			return err
+		}
+		a.Slug = s
+	}
+
 	article := map[string]interface{}{
-		"tag_id":          a.TagID,
+		"tag_id":          a.getPrimaryTagID(),
+		"tag_ids":         a.TagIDs,
 		"title":           a.Title,
+		"slug":            a.Slug,
 		"desc":            a.Desc,
 		"content":         a.Content,
+		"content_format":  a.ContentFormat,
+		"content_html":    a.ContentHtml,
+		"content_text":    a.ContentText,
 		"created_by":      a.CreatedBy,
+		"created_by_id":   a.CreatedByID,
 		"cover_image_url": a.CoverImageUrl,
-		"state":           a.State,
+		"state":           0,
+		"status":          models.ARTICLE_STATUS_DRAFT,
 	}
 
-	if err := models.AddArticle(article); err != nil {
+	id, err := models.AddArticle(article)
+	if err != nil {
 		return err
 	}
 
+	a.ID = id
+	// Cached draft and author lists now miss the article
+	clearCache(a.ID)
+	search_service.Index(a.getDocument())
 	return nil
 }
 
+// Edit replaces the article, the content keeps its format unless another one is given
 func (a *Article) Edit() error {
-	return models.EditArticle(a.ID, map[string]interface{}{
-		"tag_id":          a.TagID,
-		"title":           a.Title,
-		"desc":            a.Desc,
-		"content":         a.Content,
-		"cover_image_url": a.CoverImageUrl,
-		"state":           a.State,
-		"modified_by":     a.ModifiedBy,
-	})
+	article, err := models.GetArticle(a.ID)
+	if err != nil {
+		return err
+	}
+	if a.ContentFormat == "" {
+		a.ContentFormat = article.ContentFormat
+	}
+	if err := a.render(); err != nil {
+		return err
+	}
+
+	data := a.getEditData()
+	if err := a.setEditSlug(data, article); err != nil {
+		return err
+	}
+	if err := models.EditArticle(a.ID, data); err != nil {
+		return err
+	}
+
+	clearCache(a.ID)
+	search_service.Index(a.getDocument())
+	return nil
 }
 
 func (a *Article) Get() (*models.Article, error) {
@@ -65,6 +115,9 @@ func (a *Article) Get() (*models.Article, error) {
 			logging.Info(err)
 		} else {
 			json.Unmarshal(data, &cacheArticle)
+			if err := setCommentCounts([]*models.Article{cacheArticle}); err != nil {
+				return nil, err
+			}
 			return cacheArticle, nil
 		}
 	}
@@ -73,8 +126,12 @@ func (a *Article) Get() (*models.Article, error) {
 	if err != nil {
 		return nil, err
 	}
+	setContentDetails(article)
 
 	gredis.Set(key, article, 3600)
+	if err := setCommentCounts([]*models.Article{article}); err != nil {
+		return nil, err
+	}
 	return article, nil
 }
 
@@ -84,8 +141,11 @@ func (a *Article) GetAll() ([]*models.Article, error) {
 	)
 
 	cache := cache_service.Article{
-		TagID: a.TagID,
-		State: a.State,
+		TagIDs:      a.TagIDs,
+		TagMatchAll: a.TagMatchAll,
+		Status:      a.Status,
+		CreatedByID: a.CreatedByID,
+		AuthorID:    a.AuthorID,
 
 		PageNum:  a.PageNum,
 		PageSize: a.PageSize,
@@ -97,21 +157,32 @@ func (a *Article) GetAll() ([]*models.Article, error) {
 			logging.Info(err)
 		} else {
 			json.Unmarshal(data, &cacheArticles)
+			if err := setCommentCounts(cacheArticles); err != nil {
+				return nil, err
+			}
 			return cacheArticles, nil
 		}
 	}
 
-	articles, err := models.GetArticles(a.PageNum, a.PageSize, a.getMaps())
+	articles, err := models.GetArticles(a.PageNum, a.PageSize, a.getMaps(), a.getScopes()...)
 	if err != nil {
 		return nil, err
 	}
 
 	gredis.Set(key, articles, 3600)
+	if err := setCommentCounts(articles); err != nil {
+		return nil, err
+	}
 	return articles, nil
 }
 
 func (a *Article) Delete() error {
-	return models.DeleteArticle(a.ID)
+	if err := models.DeleteArticle(a.ID); err != nil {
+		return err
+	}
+
+	clearCache(a.ID)
+	return nil
 }
 
 func (a *Article) ExistByID() (bool, error) {
@@ -119,18 +190,54 @@ func (a *Article) ExistByID() (bool, error) {
 }
 
 func (a *Article) Count() (int, error) {
-	return models.GetArticleTotal(a.getMaps())
+	return models.GetArticleTotal(a.getMaps(), a.getScopes()...)
 }
 
 func (a *Article) getMaps() map[string]interface{} {
 	maps := make(map[string]interface{})
 	maps["deleted_on"] = 0
-	if a.State != -1 {
-		maps["state"] = a.State
+	if a.Status != "" {
+		maps["status"] = a.Status
 	}
-	if a.TagID != -1 {
-		maps["tag_id"] = a.TagID
+	if a.CreatedByID > 0 {
+		maps["created_by_id"] = a.CreatedByID
 	}
 
 	return maps
 }
+
+func (a *Article) getScopes() []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if a.AuthorID > 0 {
+		scopes = append(scopes, models.ArticleAuthorScope(a.AuthorID, ROLE_OWNER, ROLE_EDITOR))
+	}
+	if len(a.TagIDs) > 0 {
+		scopes = append(scopes, models.ArticleTagScope(a.TagIDs, a.TagMatchAll))
+	}
+
+	return scopes
+}
+
+// getPrimaryTagID is the tag stored in the deprecated tag_id column
+func (a *Article) getPrimaryTagID() int {
+	if len(a.TagIDs) == 0 {
+		return 0
+	}
+
+	return a.TagIDs[0]
+}
+
+func (a *Article) getEditData() map[string]interface{} {
+	return map[string]interface{}{
+		"tag_id":          a.getPrimaryTagID(),
+		"tag_ids":         a.TagIDs,
+		"title":           a.Title,
+		"desc":            a.Desc,
+		"content":         a.Content,
+		"content_format":  a.ContentFormat,
+		"content_html":    a.ContentHtml,
+		"content_text":    a.ContentText,
+		"cover_image_url": a.CoverImageUrl,
+		"modified_by":     a.ModifiedBy,
+	}
+}
@@ -0,0 +1,112 @@
+package article_service
+
+import (
+	"errors"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+)
+
+// Roles a user can have on a single article. The creator is always an owner;
+// roles narrow, but never widen, the RBAC permissions of the user.
+const (
+	ROLE_OWNER  = "owner"
+	ROLE_EDITOR = "editor"
+	ROLE_VIEWER = "viewer"
+)
+
+var ErrAuthorIsCreator = errors.New("the creator of an article is always its owner")
+
+var roleLevels = map[string]int{
+	ROLE_VIEWER: 1,
+	ROLE_EDITOR: 2,
+	ROLE_OWNER:  3,
+}
+
+// IsValidRole checks whether a role is a known article role
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// RoleAllows checks whether a role is at least the required one
+func RoleAllows(role, required string) bool {
+	return role != "" && roleLevels[role] >= roleLevels[required]
+}
+
+// GetRoleOf returns the role of a user on the article, empty if they have none
+func (a *Article) GetRoleOf(authID int) (string, error) {
+	if authID == 0 {
+		return "", nil
+	}
+
+	article, err := models.GetArticle(a.ID)
+	if err != nil {
+		return "", err
+	}
+	if article.CreatedByID == authID {
+		return ROLE_OWNER, nil
+	}
+
+	author, err := models.GetArticleAuthor(a.ID, authID)
+	if err != nil {
+		return "", err
+	}
+
+	return author.Role, nil
+}
+
+// GetAuthors returns the co-authors of the article, the creator not included
+func (a *Article) GetAuthors() ([]*models.ArticleAuthor, error) {
+	return models.GetArticleAuthors(a.ID)
+}
+
+// SetAuthor adds a co-author or changes their role
+func (a *Article) SetAuthor(authID int, role, createdBy string) error {
+	article, err := models.GetArticle(a.ID)
+	if err != nil {
+		return err
+	}
+	if article.CreatedByID == authID {
+		return ErrAuthorIsCreator
+	}
+
+	author, err := models.GetArticleAuthor(a.ID, authID)
+	if err != nil {
+		return err
+	}
+	if author.ID > 0 {
+		err = models.EditArticleAuthor(a.ID, authID, role)
+	} else {
+		err = models.AddArticleAuthor(map[string]interface{}{
+			"article_id": a.ID,
+			"auth_id":    authID,
+			"role":       role,
+			"created_by": createdBy,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	// The role decides whether the article is in the author's lists
+	clearListCache()
+	return nil
+}
+
+// RemoveAuthor removes a co-author, reporting whether they were one
+func (a *Article) RemoveAuthor(authID int) (bool, error) {
+	author, err := models.GetArticleAuthor(a.ID, authID)
+	if err != nil {
+		return false, err
+	}
+	if author.ID == 0 {
+		return false, nil
+	}
+
+	if err := models.DeleteArticleAuthor(a.ID, authID); err != nil {
+		return false, err
+	}
+
+	clearListCache()
+	return true, nil
+}
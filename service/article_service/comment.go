@@ -0,0 +1,34 @@
+package article_service
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+)
+
+// SetCommentsClosed stops new comments on the article, or allows them again
+func (a *Article) SetCommentsClosed(closed bool) error {
+	if err := models.EditArticleCommentsClosed(a.ID, closed); err != nil {
+		return err
+	}
+
+	clearCache(a.ID)
+	return nil
+}
+
+// setCommentCounts fills in the number of approved comments of the articles. It is not cached
+// with the articles, comments are moderated too often.
+func setCommentCounts(articles []*models.Article) error {
+	ids := make([]int, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+
+	counts, err := models.GetArticleCommentCounts(ids)
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		article.CommentCount = counts[article.ID]
+	}
+
+	return nil
+}
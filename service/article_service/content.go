@@ -0,0 +1,118 @@
+package article_service
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/markdown"
+	"github.com/EDDYCJY/go-gin-example/pkg/sanitize"
+)
+
+// Reading speeds for words and for Chinese, Japanese and Korean characters, which have no spaces
+const (
+	READING_WORDS_PER_MINUTE = 200
+	READING_CHARS_PER_MINUTE = 400
+)
+
+var blankLines = regexp.MustCompile(`\n[ \t]*\n`)
+
+// IsValidContentFormat checks whether articles can be written in a format
+func IsValidContentFormat(format string) bool {
+	return format == models.ARTICLE_FORMAT_PLAIN || format == models.ARTICLE_FORMAT_MARKDOWN || format == models.ARTICLE_FORMAT_HTML
+}
+
+// RenderContent turns content into the HTML shown to readers. Markdown and HTML fail with a
+// *sanitize.UnsafeError if they contain elements, attributes or URLs that could run scripts.
+func RenderContent(format, content string) (string, error) {
+	switch format {
+	case models.ARTICLE_FORMAT_MARKDOWN:
+		return sanitize.HTML(markdown.ToHTML(content))
+	case models.ARTICLE_FORMAT_HTML:
+		return sanitize.HTML(content)
+	default:
+		return renderPlain(content), nil
+	}
+}
+
+// renderPlain escapes plain text, blank lines separating paragraphs and newlines breaking lines
+func renderPlain(content string) string {
+	content = strings.Replace(content, "\r\n", "\n", -1)
+
+	var b strings.Builder
+	for _, p := range blankLines.Split(strings.TrimSpace(content), -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			b.WriteString("<p>" + strings.Replace(html.EscapeString(p), "\n", "<br>\n", -1) + "</p>\n")
+		}
+	}
+
+	return b.String()
+}
+
+// ReadingTime estimates the minutes it takes to read the HTML of an article, at least 1 if it has any text
+func ReadingTime(contentHtml string) int {
+	words, chars := 0, 0
+	inWord := false
+	for _, r := range sanitize.Text(contentHtml) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			chars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	if words == 0 && chars == 0 {
+		return 0
+	}
+
+	// Round up
+	perMinute := READING_WORDS_PER_MINUTE * READING_CHARS_PER_MINUTE
+	return (words*READING_CHARS_PER_MINUTE + chars*READING_WORDS_PER_MINUTE + perMinute - 1) / perMinute
+}
+
+// render fills ContentHtml from the content and its format, plain if none is given, and ContentText from ContentHtml
+func (a *Article) render() error {
+	if a.ContentFormat == "" {
+		a.ContentFormat = models.ARTICLE_FORMAT_PLAIN
+	}
+
+	contentHtml, err := RenderContent(a.ContentFormat, a.Content)
+	if err != nil {
+		return err
+	}
+
+	a.ContentHtml = contentHtml
+	a.ContentText = sanitize.Text(contentHtml)
+	return nil
+}
+
+// setContentDetails adds the table of contents and the reading time to an article. Articles
+// written before content formats were introduced are rendered here, they are plain text.
+func setContentDetails(article *models.Article) {
+	if article.ContentHtml == "" && article.Content != "" {
+		article.ContentHtml, _ = RenderContent(article.ContentFormat, article.Content)
+	}
+
+	article.Toc = sanitize.Headings(article.ContentHtml)
+	article.ReadingTime = ReadingTime(article.ContentHtml)
+}
+
+// contentText is the text of the content of an article without its markup
+func contentText(article *models.Article) string {
+	if article.ContentText != "" {
+		return article.ContentText
+	}
+	if article.ContentHtml == "" {
+		return article.Content
+	}
+
+	return sanitize.Text(article.ContentHtml)
+}
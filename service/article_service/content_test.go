@@ -0,0 +1,46 @@
+package article_service
+
+import (
+	"testing"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/sanitize"
+)
+
+func TestRenderContent(t *testing.T) {
+	tests := []struct {
+		format, content, want string
+	}{
+		{models.ARTICLE_FORMAT_PLAIN, "a <b>\nc\n\nd", "<p>a &lt;b&gt;<br>\nc</p>\n<p>d</p>\n"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "# Hi\n\n[x](/y) **z**", "<h1 id=\"hi\">Hi</h1>\n<p><a href=\"/y\">x</a> <strong>z</strong></p>\n"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "<b>open", "<b>open\n</b>"},
+		{models.ARTICLE_FORMAT_HTML, "<p>a<!-- b --></p></div>", "<p>a</p>"},
+	}
+	for _, tt := range tests {
+		got, err := RenderContent(tt.format, tt.content)
+		if err != nil || got != tt.want {
+			t.Errorf("RenderContent(%q, %q) = %q, %v, want %q", tt.format, tt.content, got, err, tt.want)
+		}
+	}
+}
+
+func TestRenderContentRejectsUnsafe(t *testing.T) {
+	tests := []struct {
+		format, content string
+	}{
+		{models.ARTICLE_FORMAT_MARKDOWN, "[x](javascript:alert(1))"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "[x](java&#x09;script:alert(1))"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "![x](data:text/html,x)"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "<javascript:alert(1)>"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "a <img src=x onerror=alert(1)>"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "<script>\nalert(1)\n</script>"},
+		{models.ARTICLE_FORMAT_MARKDOWN, "<svg onload=alert(1)>"},
+		{models.ARTICLE_FORMAT_HTML, "<p onclick=alert(1)>x</p>"},
+	}
+	for _, tt := range tests {
+		got, err := RenderContent(tt.format, tt.content)
+		if _, ok := err.(*sanitize.UnsafeError); !ok {
+			t.Errorf("RenderContent(%q, %q) = %q, %v, want an *sanitize.UnsafeError", tt.format, tt.content, got, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package article_service
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/service/view_service"
+)
+
+// POPULAR_OVERFETCH is how many ranked articles are loaded for each one asked for, to make up
+// for those that are no longer published
+const POPULAR_OVERFETCH = 3
+
+// PopularArticle is a published article with its views in the period of a ranking
+type PopularArticle struct {
+	*models.Article
+	Views int `json:"views"`
+}
+
+// GetPopular returns up to limit published articles, most viewed in the period first
+func GetPopular(period string, limit int) ([]*PopularArticle, error) {
+	ranked, err := view_service.Top(period, limit*POPULAR_OVERFETCH)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(ranked))
+	for _, r := range ranked {
+		ids = append(ids, r.ArticleID)
+	}
+	articles, err := models.GetArticlesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := setCommentCounts(articles); err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	popular := make([]*PopularArticle, 0, limit)
+	for _, r := range ranked {
+		article, ok := byID[r.ArticleID]
+		if !ok || article.DeletedOn != 0 || article.Status != models.ARTICLE_STATUS_PUBLISHED {
+			continue
+		}
+		popular = append(popular, &PopularArticle{Article: article, Views: r.Views})
+		if len(popular) == limit {
+			break
+		}
+	}
+
+	return popular, nil
+}
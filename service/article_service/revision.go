@@ -0,0 +1,111 @@
+package article_service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/diff"
+	"github.com/EDDYCJY/go-gin-example/service/search_service"
+)
+
+// DIFF_CONTEXT is the number of unchanged lines shown around each change of the content
+const DIFF_CONTEXT = 3
+
+// GetRevisions returns a page of the revisions of the article, latest first
+func (a *Article) GetRevisions() ([]*models.ArticleRevision, error) {
+	return models.GetArticleRevisions(a.ID, a.PageNum, a.PageSize)
+}
+
+func (a *Article) CountRevisions() (int, error) {
+	return models.GetArticleRevisionTotal(a.ID)
+}
+
+// GetRevision returns a revision of the article by its number, with ID 0 if there is none
+func (a *Article) GetRevision(revision int) (*models.ArticleRevision, error) {
+	return models.GetArticleRevision(a.ID, revision)
+}
+
+// Restore edits the article back to the snapshot of a revision, which is recorded as a new revision.
+// The status is left alone, it only changes through the publishing workflow, and the slug follows
+// the restored title as on Edit.
+func (a *Article) Restore(rev *models.ArticleRevision) error {
+	a.TagIDs = GetRevisionTagIDs(rev)
+	a.Title = rev.Title
+	a.Desc = rev.Desc
+	a.Content = rev.Content
+	a.ContentFormat = rev.ContentFormat
+	a.CoverImageUrl = rev.CoverImageUrl
+	if err := a.render(); err != nil {
+		return err
+	}
+
+	article, err := models.GetArticle(a.ID)
+	if err != nil {
+		return err
+	}
+
+	data := a.getEditData()
+	data["restored_from"] = rev.Revision
+	if err := a.setEditSlug(data, article); err != nil {
+		return err
+	}
+	if err := models.EditArticle(a.ID, data); err != nil {
+		return err
+	}
+
+	clearCache(a.ID)
+	search_service.Index(a.getDocument())
+	return nil
+}
+
+// GetRevisionTagIDs splits the stored tags of a revision, the first being the primary tag
+func GetRevisionTagIDs(rev *models.ArticleRevision) []int {
+	var ids []int
+	for _, s := range strings.Split(rev.TagIDs, ",") {
+		if id, err := strconv.Atoi(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// DiffRevisions describes the changes from one revision to another: the old and new value of
+// each changed field and a unified diff of the content. from may be an empty revision.
+func DiffRevisions(from, to *models.ArticleRevision) map[string]interface{} {
+	changes := make(map[string]interface{})
+	fields := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"tag_ids", from.TagIDs, to.TagIDs},
+		{"title", from.Title, to.Title},
+		{"desc", from.Desc, to.Desc},
+		{"content_format", from.ContentFormat, to.ContentFormat},
+		{"cover_image_url", from.CoverImageUrl, to.CoverImageUrl},
+		{"state", from.State, to.State},
+	}
+	for _, f := range fields {
+		if f.old != f.new {
+			changes[f.name] = map[string]interface{}{"from": f.old, "to": f.new}
+		}
+	}
+
+	lines := diff.Lines(from.Content, to.Content)
+
+	return map[string]interface{}{
+		"from":    from.Revision,
+		"to":      to.Revision,
+		"changes": changes,
+		"content": diff.Unified(revisionName(from), revisionName(to), lines, DIFF_CONTEXT),
+	}
+}
+
+func revisionName(rev *models.ArticleRevision) string {
+	if rev.Revision == 0 {
+		return "/dev/null"
+	}
+
+	return "revision " + strconv.Itoa(rev.Revision)
+}
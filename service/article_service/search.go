@@ -0,0 +1,109 @@
+package article_service
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/search"
+	"github.com/EDDYCJY/go-gin-example/service/search_service"
+)
+
+const (
+	// SEARCH_MAX_HITS bounds the matches ranked for a query before the filters are applied
+	SEARCH_MAX_HITS = 1000
+	// SNIPPET_WIDTH is the number of characters of the desc and content snippets
+	SNIPPET_WIDTH = 160
+)
+
+// SearchResult is an article found by Search with its relevance and highlighted snippets
+type SearchResult struct {
+	*models.Article
+	Score float64 `json:"score"`
+	// Highlights holds the title and snippets of the desc and content as HTML, the terms wrapped in <em>
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Search returns a page of the articles matching the keywords and the filters of the article,
+// best match first, and how many there are in total
+func (a *Article) Search(keywords string) ([]*SearchResult, int, error) {
+	terms := search.Terms(keywords)
+	hits, err := search_service.Search(terms, SEARCH_MAX_HITS)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	matching, err := models.GetMatchingArticleIDs(ids, a.getMaps(), a.getScopes()...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Keep the ranking of the hits that pass the filters
+	isMatching := make(map[int]bool, len(matching))
+	for _, id := range matching {
+		isMatching[id] = true
+	}
+	var ranked []search.Hit
+	for _, hit := range hits {
+		if isMatching[hit.ID] {
+			ranked = append(ranked, hit)
+		}
+	}
+
+	total := len(ranked)
+	if a.PageNum >= total {
+		return []*SearchResult{}, total, nil
+	}
+	end := a.PageNum + a.PageSize
+	if end > total {
+		end = total
+	}
+	ranked = ranked[a.PageNum:end]
+
+	pageIDs := make([]int, 0, len(ranked))
+	for _, hit := range ranked {
+		pageIDs = append(pageIDs, hit.ID)
+	}
+	articles, err := models.GetArticlesByIDs(pageIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := setCommentCounts(articles); err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[int]*models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	results := make([]*SearchResult, 0, len(ranked))
+	for _, hit := range ranked {
+		article, ok := byID[hit.ID]
+		if !ok {
+			// Deleted since the filters were applied
+			continue
+		}
+		results = append(results, &SearchResult{
+			Article: article,
+			Score:   hit.Score,
+			Highlights: map[string]string{
+				"title":   search.Highlight(article.Title, terms, 0),
+				"desc":    search.Highlight(article.Desc, terms, SNIPPET_WIDTH),
+				"content": search.Highlight(contentText(article), terms, SNIPPET_WIDTH),
+			},
+		})
+	}
+
+	return results, total, nil
+}
+
+// getDocument is the searchable text of the article, once rendered
+func (a *Article) getDocument() search.Document {
+	return search.Document{
+		ID:      a.ID,
+		Title:   a.Title,
+		Desc:    a.Desc,
+		Content: a.ContentText,
+	}
+}
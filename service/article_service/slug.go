@@ -0,0 +1,97 @@
+package article_service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/slug"
+)
+
+// ExistSlug checks whether another article uses the slug of the article, now or formerly
+func (a *Article) ExistSlug() (bool, error) {
+	return models.ExistArticleSlug(a.Slug, a.ID)
+}
+
+// ResolveSlug finds the live article with a slug. For a former slug it returns ID 0 and the current
+// slug of the article to redirect to instead, and neither if no live article ever had the slug.
+func ResolveSlug(s string) (int, string, error) {
+	id, err := models.GetArticleIDBySlug(s)
+	if err != nil || id > 0 {
+		return id, "", err
+	}
+
+	current, err := models.GetCurrentArticleSlug(s)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return 0, current, nil
+}
+
+// newSlug generates a slug from the title, numbered if other articles use it already
+func (a *Article) newSlug() (string, error) {
+	base := baseSlug(a.Title)
+	for n := 1; ; n++ {
+		s := base
+		if n > 1 {
+			s = slug.WithSuffix(base, n)
+		}
+
+		exists, err := models.ExistArticleSlug(s, a.ID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return s, nil
+		}
+	}
+}
+
+// setEditSlug adds the slug to the edit data: the one chosen by the user if any, otherwise a new
+// one when the title changes and the article, as it is before the edit, still has the slug
+// generated from its old title
+func (a *Article) setEditSlug(data map[string]interface{}, article *models.Article) error {
+	if a.Slug != "" {
+		data["slug"] = a.Slug
+		return nil
+	}
+	if article.Title == a.Title || !isGeneratedSlug(article) {
+		return nil
+	}
+
+	s, err := a.newSlug()
+	if err != nil {
+		return err
+	}
+
+	data["slug"] = s
+	return nil
+}
+
+func baseSlug(title string) string {
+	if s := slug.Make(title); s != "" {
+		return s
+	}
+
+	return slug.Hash(title)
+}
+
+// isGeneratedSlug reports whether the slug of an article was generated from its title,
+// numbered or not, or given to it when slugs were introduced, as opposed to chosen by a user
+func isGeneratedSlug(article *models.Article) bool {
+	if article.Slug == "" || article.Slug == "article-"+strconv.Itoa(article.ID) {
+		return true
+	}
+
+	base := baseSlug(article.Title)
+	if article.Slug == base {
+		return true
+	}
+	if !strings.HasPrefix(article.Slug, base+"-") {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimPrefix(article.Slug, base+"-"))
+
+	return err == nil
+}
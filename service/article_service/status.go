@@ -0,0 +1,172 @@
+package article_service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+)
+
+var (
+	ErrTransitionInvalid = errors.New("article cannot move from its current status to the requested one")
+	ErrPublishAtInvalid  = errors.New("scheduled articles need a publishing time in the future")
+	ErrStatusChanged     = errors.New("article status was changed by another request")
+)
+
+// transitions lists the statuses an article may move to from each status
+var transitions = map[string][]string{
+	models.ARTICLE_STATUS_DRAFT:     {models.ARTICLE_STATUS_IN_REVIEW, models.ARTICLE_STATUS_SCHEDULED, models.ARTICLE_STATUS_PUBLISHED},
+	models.ARTICLE_STATUS_IN_REVIEW: {models.ARTICLE_STATUS_DRAFT, models.ARTICLE_STATUS_SCHEDULED, models.ARTICLE_STATUS_PUBLISHED},
+	models.ARTICLE_STATUS_SCHEDULED: {models.ARTICLE_STATUS_DRAFT, models.ARTICLE_STATUS_SCHEDULED, models.ARTICLE_STATUS_PUBLISHED},
+	models.ARTICLE_STATUS_PUBLISHED: {models.ARTICLE_STATUS_DRAFT, models.ARTICLE_STATUS_ARCHIVED},
+	models.ARTICLE_STATUS_ARCHIVED:  {models.ARTICLE_STATUS_DRAFT, models.ARTICLE_STATUS_PUBLISHED},
+}
+
+// IsValidStatus checks whether a status is part of the publishing workflow
+func IsValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// CanTransition checks whether an article may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPublishTransition reports whether a transition decides what readers see, as opposed to
+// moving an article between draft and review
+func IsPublishTransition(from, to string) bool {
+	return !isEditorial(from) || !isEditorial(to)
+}
+
+func isEditorial(status string) bool {
+	return status == models.ARTICLE_STATUS_DRAFT || status == models.ARTICLE_STATUS_IN_REVIEW
+}
+
+// GetUncached returns the article from the database, e.g. to check its current status
+func (a *Article) GetUncached() (*models.Article, error) {
+	return models.GetArticle(a.ID)
+}
+
+// Transition moves the article from the status it has in article, as loaded by GetUncached, to
+// another one, publishAt being the publishing time of a scheduled article. It fails with
+// ErrStatusChanged if the status was changed since.
+func (a *Article) Transition(article *models.Article, to string, publishAt int) error {
+	if !CanTransition(article.Status, to) {
+		return ErrTransitionInvalid
+	}
+
+	now := int(time.Now().Unix())
+	data := map[string]interface{}{
+		"status":      to,
+		"state":       0,
+		"modified_by": a.ModifiedBy,
+	}
+	switch to {
+	case models.ARTICLE_STATUS_SCHEDULED:
+		if publishAt <= now {
+			return ErrPublishAtInvalid
+		}
+		data["publish_at"] = publishAt
+	case models.ARTICLE_STATUS_PUBLISHED:
+		data["state"] = 1
+		// Republishing an archived article keeps its original date
+		if article.Status != models.ARTICLE_STATUS_ARCHIVED || article.PublishAt == 0 {
+			data["publish_at"] = now
+		}
+	case models.ARTICLE_STATUS_DRAFT, models.ARTICLE_STATUS_IN_REVIEW:
+		data["publish_at"] = 0
+	}
+
+	ok, err := models.EditArticleStatus(a.ID, article.Status, data)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrStatusChanged
+	}
+
+	clearCache(a.ID)
+	return nil
+}
+
+// PublishDue publishes the scheduled articles whose publishing time has come and
+// returns how many it published
+func PublishDue() (int, error) {
+	now := int(time.Now().Unix())
+	ids, err := models.GetDueArticleIDs(now)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, id := range ids {
+		ok, err := models.PublishDueArticle(id, now)
+		if err != nil {
+			return published, err
+		}
+		// Another server process may have published it first
+		if ok {
+			clearCache(id)
+			published++
+		}
+	}
+
+	return published, nil
+}
+
+// StartScheduler publishes scheduled articles in the background every interval
+func StartScheduler(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			published, err := PublishDue()
+			if err != nil {
+				logging.Warn("publishing scheduled articles failed:", err)
+			}
+			if published > 0 {
+				logging.Info("published scheduled articles:", published)
+			}
+
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// clearCache drops the cached article and every cached article list and feed, which may include it
+func clearCache(id int) {
+	cache := cache_service.Article{ID: id}
+	if _, err := gredis.Delete(cache.GetArticleKey()); err != nil {
+		logging.Warn("article cache invalidation failed:", err)
+	}
+	clearListCache()
+	clearFeedCache()
+}
+
+// clearListCache drops every cached article list, for changes that move articles between lists
+// without changing them, such as sharing an article with a co-author
+func clearListCache() {
+	cache := cache_service.Article{}
+	if err := gredis.LikeDeletes(cache.GetArticlesPrefix()); err != nil {
+		logging.Warn("article cache invalidation failed:", err)
+	}
+}
+
+func clearFeedCache() {
+	feeds := cache_service.Feed{}
+	if err := gredis.LikeDeletes(feeds.GetFeedsPrefix()); err != nil {
+		logging.Warn("feed cache invalidation failed:", err)
+	}
+}
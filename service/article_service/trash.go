@@ -0,0 +1,67 @@
+package article_service
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+	"github.com/EDDYCJY/go-gin-example/service/search_service"
+)
+
+// GetTrash returns a page of the deleted articles, latest deleted first
+func (a *Article) GetTrash() ([]*models.Article, error) {
+	return models.GetDeletedArticles(a.PageNum, a.PageSize)
+}
+
+func (a *Article) CountTrash() (int, error) {
+	return models.GetDeletedArticleTotal()
+}
+
+func (a *Article) ExistInTrash() (bool, error) {
+	return models.ExistDeletedArticleByID(a.ID)
+}
+
+// Undelete restores the article from the trash
+func (a *Article) Undelete() error {
+	if err := models.RestoreArticle(a.ID); err != nil {
+		return err
+	}
+
+	clearCache(a.ID)
+	return nil
+}
+
+// Purge permanently deletes the article from the trash
+func (a *Article) Purge() error {
+	if err := models.PurgeArticle(a.ID); err != nil {
+		return err
+	}
+
+	search_service.Remove(a.ID)
+	return nil
+}
+
+// Forget removes articles purged outside of the service from the search index and the cache
+func Forget(ids []int) {
+	for _, id := range ids {
+		search_service.Remove(id)
+	}
+	ClearCaches(ids)
+}
+
+// ClearCaches drops the cached copies of articles changed outside of the service,
+// such as the articles a purged tag was removed from, along with every cached list and feed
+func ClearCaches(ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		cache := cache_service.Article{ID: id}
+		if _, err := gredis.Delete(cache.GetArticleKey()); err != nil {
+			logging.Warn("article cache invalidation failed:", err)
+		}
+	}
+	clearListCache()
+	clearFeedCache()
+}
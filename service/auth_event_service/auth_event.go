@@ -0,0 +1,147 @@
+package auth_event_service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/tealeg/xlsx"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/export"
+	"github.com/EDDYCJY/go-gin-example/pkg/file"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+)
+
+const (
+	EVENT_LOGIN_SUCCESS   = "login_success"
+	EVENT_LOGIN_FAILURE   = "login_failure"
+	EVENT_LOGOUT          = "logout"
+	EVENT_TOKEN_REVOKED   = "token_revoked"
+	EVENT_PASSWORD_CHANGE = "password_change"
+	EVENT_PASSWORD_RESET  = "password_reset"
+)
+
+type AuthEvent struct {
+	Username  string
+	Event     string
+	IP        string
+	UserAgent string
+	Detail    string
+
+	// From and To bound the query on created_on, 0 leaves the side open
+	From int
+	To   int
+
+	PageNum  int
+	PageSize int
+}
+
+// Record persists the event. The audit trail must not break authentication,
+// so a failed write is only logged.
+func (a *AuthEvent) Record() {
+	err := models.AddAuthEvent(map[string]interface{}{
+		"username":   truncate(a.Username, 100),
+		"event":      a.Event,
+		"ip":         truncate(a.IP, 45),
+		"user_agent": truncate(a.UserAgent, 255),
+		"detail":     truncate(a.Detail, 255),
+	})
+	if err != nil {
+		logging.Warn("auth event write failed:", a.Event, a.Username, err)
+	}
+}
+
+func (a *AuthEvent) GetAll() ([]*models.AuthEvent, error) {
+	return models.GetAuthEvents(a.PageNum, a.PageSize, a.getMaps(), a.From, a.To)
+}
+
+func (a *AuthEvent) Count() (int, error) {
+	return models.GetAuthEventTotal(a.getMaps(), a.From, a.To)
+}
+
+// Export writes the matching events to an xlsx file and returns its name. The name
+// carries a random part as export files are served without authentication.
+func (a *AuthEvent) Export() (string, error) {
+	a.PageSize = 0
+	events, err := a.GetAll()
+	if err != nil {
+		return "", err
+	}
+
+	xlsFile := xlsx.NewFile()
+	sheet, err := xlsFile.AddSheet("Auth Events")
+	if err != nil {
+		return "", err
+	}
+
+	titles := []string{"ID", "Time", "Event", "Username", "IP", "User Agent", "Detail"}
+	row := sheet.AddRow()
+
+	var cell *xlsx.Cell
+	for _, title := range titles {
+		cell = row.AddCell()
+		cell.Value = title
+	}
+
+	for _, v := range events {
+		values := []string{
+			strconv.Itoa(v.ID),
+			time.Unix(int64(v.CreatedOn), 0).Format(time.RFC3339),
+			v.Event,
+			v.Username,
+			v.IP,
+			v.UserAgent,
+			v.Detail,
+		}
+
+		row = sheet.AddRow()
+		for _, value := range values {
+			cell = row.AddCell()
+			cell.Value = value
+		}
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	filename := "auth-events-" + strconv.Itoa(int(time.Now().Unix())) + "-" + hex.EncodeToString(suffix) + export.EXT
+
+	dirFullPath := export.GetExcelFullPath()
+	err = file.IsNotExistMkDir(dirFullPath)
+	if err != nil {
+		return "", err
+	}
+
+	err = xlsFile.Save(dirFullPath + filename)
+	if err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+func (a *AuthEvent) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+	if a.Username != "" {
+		maps["username"] = a.Username
+	}
+	if a.Event != "" {
+		maps["event"] = a.Event
+	}
+	if a.IP != "" {
+		maps["ip"] = a.IP
+	}
+
+	return maps
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+
+	return s
+}
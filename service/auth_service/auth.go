@@ -1,12 +1,220 @@
 package auth_service
 
-import "github.com/EDDYCJY/go-gin-example/models"
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/pwpolicy"
+	"github.com/EDDYCJY/go-gin-example/pkg/rbac"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+// ErrAuthDisabled is returned by Check for disabled accounts
+var ErrAuthDisabled = models.ErrAuthDisabled
 
 type Auth struct {
-	Username string
-	Password string
+	ID          int
+	Username    string
+	Password    string
+	Role        string
+	Email       string
+	DisplayName string
+	Status      int
+
+	PageNum  int
+	PageSize int
 }
 
 func (a *Auth) Check() (bool, error) {
 	return models.CheckAuth(a.Username, a.Password)
 }
+
+func (a *Auth) CheckPassword() (bool, error) {
+	return models.CheckAuthPassword(a.ID, a.Password)
+}
+
+func (a *Auth) Get() (*models.Auth, error) {
+	if a.ID > 0 {
+		return models.GetAuth(a.ID)
+	}
+
+	return models.GetAuthByUsername(a.Username)
+}
+
+func (a *Auth) GetAll() ([]*models.Auth, error) {
+	return models.GetAuths(a.PageNum, a.PageSize, a.getMaps())
+}
+
+func (a *Auth) Count() (int, error) {
+	return models.GetAuthTotal(a.getMaps())
+}
+
+func (a *Auth) ExistByID() (bool, error) {
+	return models.ExistAuthByID(a.ID)
+}
+
+func (a *Auth) ExistByUsername() (bool, error) {
+	return models.ExistAuthByUsername(a.Username)
+}
+
+func (a *Auth) ExistByEmail() (bool, error) {
+	return models.ExistAuthByEmail(a.Email, a.ID)
+}
+
+// Register adds a new active reader account, the password has to satisfy the policy
+func (a *Auth) Register() error {
+	if err := pwpolicy.Validate(a.Password); err != nil {
+		return err
+	}
+
+	password, err := models.HashPassword(a.Password)
+	if err != nil {
+		return err
+	}
+
+	return models.AddAuth(map[string]interface{}{
+		"username":     a.Username,
+		"password":     password,
+		"role":         rbac.ROLE_READER,
+		"email":        a.Email,
+		"display_name": a.DisplayName,
+		"status":       models.AUTH_STATUS_ACTIVE,
+	})
+}
+
+// EditProfile updates the self-service profile fields
+func (a *Auth) EditProfile() error {
+	return models.EditAuth(a.ID, map[string]interface{}{
+		"email":        a.Email,
+		"display_name": a.DisplayName,
+	})
+}
+
+// ChangePassword stores a new password hash once the password satisfies the policy
+// and is none of the recent passwords of the account
+func (a *Auth) ChangePassword() error {
+	auth, err := models.GetAuth(a.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.checkPassword(auth); err != nil {
+		return err
+	}
+
+	return a.setPassword(auth)
+}
+
+// checkPassword applies the password policy, including reuse of the last PasswordHistory passwords
+func (a *Auth) checkPassword(auth *models.Auth) error {
+	if err := pwpolicy.Validate(a.Password); err != nil {
+		return err
+	}
+
+	if setting.AppSetting.PasswordHistory < 1 {
+		return nil
+	}
+
+	hashes, err := models.GetAuthPasswordHistory(auth.ID, setting.AppSetting.PasswordHistory-1)
+	if err != nil {
+		return err
+	}
+	for _, hash := range append(hashes, auth.Password) {
+		if models.CheckPasswordHash(hash, a.Password) {
+			return pwpolicy.ErrReused
+		}
+	}
+
+	return nil
+}
+
+// setPassword replaces the password hash, keeping the old one in the history
+func (a *Auth) setPassword(auth *models.Auth) error {
+	password, err := models.HashPassword(a.Password)
+	if err != nil {
+		return err
+	}
+
+	err = models.EditAuth(auth.ID, map[string]interface{}{
+		"password": password,
+	})
+	if err != nil {
+		return err
+	}
+
+	if keep := setting.AppSetting.PasswordHistory - 1; keep > 0 {
+		return models.AddAuthPasswordHistory(auth.ID, auth.Password, keep)
+	}
+
+	return nil
+}
+
+// Edit updates the administrative fields, disabling an account revokes all of its sessions
+func (a *Auth) Edit() error {
+	data := make(map[string]interface{})
+	if a.Role != "" {
+		data["role"] = a.Role
+	}
+	if a.Status >= 0 {
+		data["status"] = a.Status
+	}
+
+	if err := models.EditAuth(a.ID, data); err != nil {
+		return err
+	}
+
+	if a.RevokesSessions() {
+		return a.revokeSessions()
+	}
+
+	return nil
+}
+
+// RevokesSessions reports whether Edit signs the account out of all of its sessions,
+// as it does when disabling the account or setting its role, which the tokens carry
+func (a *Auth) RevokesSessions() bool {
+	return a.Role != "" || a.Status == models.AUTH_STATUS_DISABLED
+}
+
+// Delete removes the account and revokes all of its sessions
+func (a *Auth) Delete() error {
+	auth, err := a.Get()
+	if err != nil {
+		return err
+	}
+
+	if err := models.DeleteAuth(a.ID); err != nil {
+		return err
+	}
+	if err := models.DeleteApiKeysByAuth(a.ID); err != nil {
+		return err
+	}
+	if err := models.DeleteAuthPasswordHistory(a.ID); err != nil {
+		return err
+	}
+	if err := models.DeleteArticleAuthorsByAuth(a.ID); err != nil {
+		return err
+	}
+
+	return jwt_redis_service.DeleteAllSessions(auth.Username)
+}
+
+func (a *Auth) revokeSessions() error {
+	auth, err := a.Get()
+	if err != nil {
+		return err
+	}
+
+	return jwt_redis_service.DeleteAllSessions(auth.Username)
+}
+
+func (a *Auth) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+	if a.Role != "" {
+		maps["role"] = a.Role
+	}
+	if a.Status >= 0 {
+		maps["status"] = a.Status
+	}
+
+	return maps
+}
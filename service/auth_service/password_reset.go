@@ -0,0 +1,91 @@
+package auth_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/mail"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+	"github.com/EDDYCJY/go-gin-example/service/login_guard_service"
+)
+
+// ErrPasswordResetInvalid is returned by ResetPassword for unknown, used or expired tokens
+var ErrPasswordResetInvalid = jwt_redis_service.ErrPasswordResetInvalid
+
+const passwordResetBody = `Hello %s,
+
+someone asked to reset the password of your account. If it was you, open the link
+below within %d minutes to choose a new password:
+
+%s
+
+If you did not ask for it, you can ignore this mail, your password stays unchanged.
+`
+
+// RequestPasswordReset mails a reset link to the active account with the given email.
+// Unknown or disabled accounts are silently skipped, so callers cannot tell them apart.
+func (a *Auth) RequestPasswordReset() error {
+	auth, err := models.GetAuthByEmail(a.Email)
+	if err != nil {
+		return err
+	}
+	if auth.ID == 0 || auth.Status != models.AUTH_STATUS_ACTIVE {
+		return nil
+	}
+
+	token, err := util.GeneratePasswordResetToken(auth.Username)
+	if err != nil {
+		return err
+	}
+
+	name := auth.DisplayName
+	if name == "" {
+		name = auth.Username
+	}
+	link := strings.Replace(setting.AppSetting.PasswordResetUrl, "%s", token, 1)
+
+	return mail.Send(&mail.Message{
+		To:      []string{auth.Email},
+		Subject: "Reset your password",
+		Body:    fmt.Sprintf(passwordResetBody, name, int(setting.AppSetting.PasswordResetExpire/time.Minute), link),
+	})
+}
+
+// ResetPassword sets a new password with a reset token, revokes every session of the
+// account and lifts a login lockout, as the old password may be what was being guessed.
+// The token stays valid if the password is rejected by the policy.
+func (a *Auth) ResetPassword(token string) error {
+	username, err := util.GetPasswordResetToken(token)
+	if err != nil {
+		return err
+	}
+
+	auth, err := models.GetAuthByUsername(username)
+	if err != nil {
+		return err
+	}
+	if auth.ID == 0 || auth.Status != models.AUTH_STATUS_ACTIVE {
+		return ErrPasswordResetInvalid
+	}
+
+	a.ID, a.Username = auth.ID, auth.Username
+	if err := a.checkPassword(auth); err != nil {
+		return err
+	}
+
+	if _, err := util.ConsumePasswordResetToken(token); err != nil {
+		return err
+	}
+	if err := a.setPassword(auth); err != nil {
+		return err
+	}
+
+	guard := login_guard_service.Guard{Username: username}
+	guard.Unlock()
+
+	return jwt_redis_service.DeleteAllSessions(username)
+}
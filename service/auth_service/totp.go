@@ -0,0 +1,195 @@
+package auth_service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+const (
+	TOTP_ISSUER         = "gin-blog"
+	RECOVERY_CODE_COUNT = 10
+)
+
+var (
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTOTPNotEnrolled    = errors.New("two-factor authentication enrollment was not started")
+	ErrTOTPCodeInvalid    = errors.New("verification code is invalid")
+)
+
+// TOTPEnrollment is a pending TOTP secret waiting to be confirmed with a first code
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// EnrollTOTP generates a new, not yet enabled, TOTP secret for the account
+func (a *Auth) EnrollTOTP() (*TOTPEnrollment, error) {
+	auth, err := a.Get()
+	if err != nil {
+		return nil, err
+	}
+	if auth.TOTPEnabled == 1 {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := models.EditAuth(auth.ID, map[string]interface{}{"totp_secret": secret}); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    util.GetTOTPProvisioningURI(TOTP_ISSUER, auth.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once a code from the pending secret
+// verifies, returning the plain recovery codes. Only their hashes are stored.
+func (a *Auth) ConfirmTOTP(code string) ([]string, error) {
+	auth, err := a.Get()
+	if err != nil {
+		return nil, err
+	}
+	if auth.TOTPEnabled == 1 {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if auth.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	ok, err := verifyTOTP(auth, normalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTOTPCodeInvalid
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.EditAuth(auth.ID, map[string]interface{}{
+		"totp_enabled":        1,
+		"totp_recovery_codes": strings.Join(hashes, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off after verifying a code or recovery code
+func (a *Auth) DisableTOTP(code string) error {
+	auth, err := a.Get()
+	if err != nil {
+		return err
+	}
+	if auth.TOTPEnabled != 1 {
+		return ErrTOTPNotEnabled
+	}
+
+	ok, err := VerifySecondFactor(auth, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTOTPCodeInvalid
+	}
+
+	return models.EditAuth(auth.ID, map[string]interface{}{
+		"totp_secret":         "",
+		"totp_enabled":        0,
+		"totp_recovery_codes": "",
+	})
+}
+
+// VerifySecondFactor checks a TOTP code, or else redeems a recovery code, of an enrolled account
+func VerifySecondFactor(auth *models.Auth, code string) (bool, error) {
+	code = normalizeCode(code)
+	if len(code) == util.TOTP_DIGITS {
+		return verifyTOTP(auth, code)
+	}
+
+	return redeemRecoveryCode(auth, code)
+}
+
+// verifyTOTP checks a TOTP code and marks its time step as used, so it cannot be replayed
+func verifyTOTP(auth *models.Auth, code string) (bool, error) {
+	step, ok := util.ValidateTOTP(auth.TOTPSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	ttl := util.TOTP_PERIOD * (2*util.TOTP_SKEW + 1)
+	fresh, err := jwt_redis_service.MarkTOTPUsed(auth.Username, step, ttl)
+	if err != nil {
+		if jwt_redis_service.GetFailurePolicy() == jwt_redis_service.FAILURE_POLICY_CLOSED {
+			return false, err
+		}
+		logging.Warn("totp replay check skipped:", err)
+		return true, nil
+	}
+
+	return fresh, nil
+}
+
+// redeemRecoveryCode removes a matching recovery code from the account
+func redeemRecoveryCode(auth *models.Auth, code string) (bool, error) {
+	if code == "" || auth.TOTPRecoveryCodes == "" {
+		return false, nil
+	}
+
+	hash := util.EncodeSHA256(code)
+	hashes := strings.Split(auth.TOTPRecoveryCodes, ",")
+	for i, h := range hashes {
+		if h != hash {
+			continue
+		}
+
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		return models.ReplaceAuthRecoveryCodes(auth.ID, auth.TOTPRecoveryCodes, strings.Join(remaining, ","))
+	}
+
+	return false, nil
+}
+
+// generateRecoveryCodes returns recovery codes formatted as xxxxx-xxxxx, with their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, RECOVERY_CODE_COUNT)
+	hashes := make([]string, 0, RECOVERY_CODE_COUNT)
+	for i := 0; i < RECOVERY_CODE_COUNT; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(b)
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, util.EncodeSHA256(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeCode strips the separators users tend to type along with a code
+func normalizeCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.Replace(code, "-", "", -1)
+	return strings.Replace(code, " ", "", -1)
+}
@@ -8,9 +8,12 @@ import (
 )
 
 type Article struct {
-	ID    int
-	TagID int
-	State int
+	ID          int
+	TagIDs      []int
+	TagMatchAll bool
+	Status      string
+	CreatedByID int
+	AuthorID    int
 
 	PageNum  int
 	PageSize int
@@ -20,20 +23,38 @@ func (a *Article) GetArticleKey() string {
 	return e.CACHE_ARTICLE + "_" + strconv.Itoa(a.ID)
 }
 
+// GetArticlesPrefix is the common prefix of the keys of all article lists
+func (a *Article) GetArticlesPrefix() string {
+	return e.CACHE_ARTICLE + "_LIST"
+}
+
 func (a *Article) GetArticlesKey() string {
 	keys := []string{
-		e.CACHE_ARTICLE,
-		"LIST",
+		a.GetArticlesPrefix(),
 	}
 
 	if a.ID > 0 {
 		keys = append(keys, strconv.Itoa(a.ID))
 	}
-	if a.TagID > 0 {
-		keys = append(keys, strconv.Itoa(a.TagID))
+	if len(a.TagIDs) > 0 {
+		tagIDs := make([]string, 0, len(a.TagIDs))
+		for _, id := range a.TagIDs {
+			tagIDs = append(tagIDs, strconv.Itoa(id))
+		}
+		match := "ANY"
+		if a.TagMatchAll {
+			match = "ALL"
+		}
+		keys = append(keys, "T"+strings.Join(tagIDs, ",")+match)
+	}
+	if a.Status != "" {
+		keys = append(keys, a.Status)
+	}
+	if a.CreatedByID > 0 {
+		keys = append(keys, "C"+strconv.Itoa(a.CreatedByID))
 	}
-	if a.State >= 0 {
-		keys = append(keys, strconv.Itoa(a.State))
+	if a.AuthorID > 0 {
+		keys = append(keys, "A"+strconv.Itoa(a.AuthorID))
 	}
 	if a.PageNum > 0 {
 		keys = append(keys, strconv.Itoa(a.PageNum))
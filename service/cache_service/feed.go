@@ -0,0 +1,31 @@
+package cache_service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+)
+
+type Feed struct {
+	Format string
+	TagID  int
+}
+
+// GetFeedsPrefix is the common prefix of the keys of all feeds
+func (f *Feed) GetFeedsPrefix() string {
+	return e.CACHE_FEED
+}
+
+func (f *Feed) GetFeedKey() string {
+	keys := []string{
+		f.GetFeedsPrefix(),
+		f.Format,
+	}
+
+	if f.TagID > 0 {
+		keys = append(keys, "T"+strconv.Itoa(f.TagID))
+	}
+
+	return strings.Join(keys, "_")
+}
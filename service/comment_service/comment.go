@@ -0,0 +1,190 @@
+package comment_service
+
+import (
+	"errors"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+)
+
+// MODERATE_MAX_IDS bounds the number of comments moderated at once
+const MODERATE_MAX_IDS = 100
+
+var ErrParentInvalid = errors.New("the comment replied to is not an approved comment of the article")
+
+type Comment struct {
+	ID        int
+	ArticleID int
+	ParentID  int
+	AuthID    int
+	CreatedBy string
+	Content   string
+	State     string
+
+	PageNum  int
+	PageSize int
+}
+
+// Thread is a comment with its replies. Deleted comments only keep their place in the thread,
+// their author and content are left out.
+type Thread struct {
+	*models.ArticleComment
+	Replies []*Thread `json:"replies"`
+}
+
+// IsValidState checks whether a state is a known comment state
+func IsValidState(state string) bool {
+	switch state {
+	case models.COMMENT_STATE_PENDING, models.COMMENT_STATE_APPROVED, models.COMMENT_STATE_SPAM, models.COMMENT_STATE_DELETED:
+		return true
+	}
+
+	return false
+}
+
+// IsModerationState checks whether moderators can move comments to a state
+func IsModerationState(state string) bool {
+	return state != models.COMMENT_STATE_PENDING && IsValidState(state)
+}
+
+// Add posts the comment, approved at once if approve is set and pending moderation otherwise.
+// Replies need an approved comment of the same article as parent.
+func (c *Comment) Add(approve bool) error {
+	rootID := 0
+	if c.ParentID > 0 {
+		parent, err := models.GetArticleComment(c.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.ID == 0 || parent.ArticleID != c.ArticleID || parent.State != models.COMMENT_STATE_APPROVED {
+			return ErrParentInvalid
+		}
+
+		rootID = parent.RootID
+		if rootID == 0 {
+			rootID = parent.ID
+		}
+	}
+
+	c.State = models.COMMENT_STATE_PENDING
+	if approve {
+		c.State = models.COMMENT_STATE_APPROVED
+	}
+
+	id, err := models.AddArticleComment(map[string]interface{}{
+		"article_id": c.ArticleID,
+		"parent_id":  c.ParentID,
+		"root_id":    rootID,
+		"auth_id":    c.AuthID,
+		"created_by": c.CreatedBy,
+		"content":    c.Content,
+		"state":      c.State,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.ID = id
+	return nil
+}
+
+// Get returns the comment, with ID 0 if it does not exist
+func (c *Comment) Get() (*models.ArticleComment, error) {
+	return models.GetArticleComment(c.ID)
+}
+
+// GetThreads returns a page of the threads of the article, each top-level comment with its
+// approved replies nested. Replies to comments that are not shown are left out with them.
+func (c *Comment) GetThreads() ([]*Thread, error) {
+	roots, err := models.GetArticleCommentThreads(c.ArticleID, c.PageNum, c.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rootIDs := make([]int, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+	replies, err := models.GetArticleCommentReplies(rootIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]*Thread, 0, len(roots))
+	nodes := make(map[int]*Thread, len(roots)+len(replies))
+	for _, root := range roots {
+		nodes[root.ID] = &Thread{ArticleComment: root, Replies: []*Thread{}}
+		threads = append(threads, nodes[root.ID])
+	}
+	// Replies come oldest first, after the comment they reply to
+	for _, reply := range replies {
+		parent, ok := nodes[reply.ParentID]
+		if !ok {
+			continue
+		}
+		nodes[reply.ID] = &Thread{ArticleComment: reply, Replies: []*Thread{}}
+		parent.Replies = append(parent.Replies, nodes[reply.ID])
+	}
+
+	return prune(threads), nil
+}
+
+// CountThreads counts the threads of the article
+func (c *Comment) CountThreads() (int, error) {
+	return models.GetArticleCommentThreadTotal(c.ArticleID)
+}
+
+// GetAll returns a page of the comments in the state of the comment, of its article if it has one, latest first
+func (c *Comment) GetAll() ([]*models.ArticleComment, error) {
+	return models.GetArticleComments(c.PageNum, c.PageSize, c.getMaps())
+}
+
+// Count counts the comments in the state of the comment, of its article if it has one
+func (c *Comment) Count() (int, error) {
+	return models.GetArticleCommentTotal(c.getMaps())
+}
+
+// Delete marks the comment as deleted, its replies stay in the thread
+func (c *Comment) Delete(deletedBy string) error {
+	_, err := models.EditArticleCommentsState([]int{c.ID}, models.COMMENT_STATE_DELETED, deletedBy)
+	return err
+}
+
+// Moderate moves comments to a state, returning the number of comments that changed
+func Moderate(ids []int, state, moderatedBy string) (int, error) {
+	return models.EditArticleCommentsState(ids, state, moderatedBy)
+}
+
+func (c *Comment) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+	maps["state"] = c.State
+	if c.ArticleID > 0 {
+		maps["article_id"] = c.ArticleID
+	}
+
+	return maps
+}
+
+// prune drops the deleted comments left without replies and hides the author and content of the others
+func prune(threads []*Thread) []*Thread {
+	kept := threads[:0]
+	for _, t := range threads {
+		t.Replies = prune(t.Replies)
+		if t.State != models.COMMENT_STATE_DELETED {
+			kept = append(kept, t)
+			continue
+		}
+		if len(t.Replies) == 0 {
+			continue
+		}
+
+		placeholder := *t.ArticleComment
+		placeholder.AuthID = 0
+		placeholder.CreatedBy = ""
+		placeholder.Content = ""
+		placeholder.ModeratedBy = ""
+		t.ArticleComment = &placeholder
+		kept = append(kept, t)
+	}
+
+	return kept
+}
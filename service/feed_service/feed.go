@@ -0,0 +1,186 @@
+package feed_service
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/feed"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+	"github.com/EDDYCJY/go-gin-example/service/cache_service"
+)
+
+var ErrTagNotExist = errors.New("the tag of the feed does not exist")
+
+// Names of the feed files under /feeds and /feeds/tags/:id
+var fileNames = map[string]string{
+	feed.FORMAT_RSS:  "rss.xml",
+	feed.FORMAT_ATOM: "atom.xml",
+	feed.FORMAT_JSON: "feed.json",
+}
+
+// Feed is the feed of the latest published articles, carrying the tag unless TagID is 0
+type Feed struct {
+	Format string
+	TagID  int
+}
+
+// Document is a rendered feed with the validators clients revalidate their copy with
+type Document struct {
+	Body        []byte `json:"body"`
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+	// LastModified is when the latest article in the feed changed, zero for a feed without articles
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Get returns the rendered feed, cached for FeedCacheExpire or until an article changes. It fails
+// with ErrTagNotExist for the feed of a tag that does not exist.
+func (f *Feed) Get() (*Document, error) {
+	cache := cache_service.Feed{Format: f.Format, TagID: f.TagID}
+	key := cache.GetFeedKey()
+	if gredis.Exists(key) {
+		data, err := gredis.Get(key)
+		if err != nil {
+			logging.Info(err)
+		} else {
+			var doc Document
+			if err := json.Unmarshal(data, &doc); err == nil {
+				return &doc, nil
+			}
+		}
+	}
+
+	doc, err := f.render()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gredis.Set(key, doc, util.Seconds(setting.AppSetting.FeedCacheExpire)); err != nil {
+		logging.Warn("feed caching failed:", err)
+	}
+	return doc, nil
+}
+
+// Path is where the feed is served, relative to PrefixUrl
+func (f *Feed) Path() string {
+	if f.TagID > 0 {
+		return "/feeds/tags/" + strconv.Itoa(f.TagID) + "/" + fileNames[f.Format]
+	}
+
+	return "/feeds/" + fileNames[f.Format]
+}
+
+func (f *Feed) render() (*Document, error) {
+	title := setting.AppSetting.FeedTitle
+	maps := map[string]interface{}{
+		"status":     models.ARTICLE_STATUS_PUBLISHED,
+		"deleted_on": 0,
+	}
+	scopes := []func(*gorm.DB) *gorm.DB{models.ArticleLatestScope()}
+	if f.TagID > 0 {
+		tag, err := models.GetTag(f.TagID)
+		if err != nil {
+			return nil, err
+		}
+		if tag.ID == 0 {
+			return nil, ErrTagNotExist
+		}
+		title += " - " + tag.Name
+		scopes = append(scopes, models.ArticleTagScope([]int{f.TagID}, false))
+	}
+
+	articles, err := models.GetArticles(0, setting.AppSetting.FeedSize, maps, scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.TrimRight(setting.AppSetting.PrefixUrl, "/")
+	fd := feed.Feed{
+		Title:       title,
+		Description: setting.AppSetting.FeedDescription,
+		Link:        prefix + "/",
+		FeedUrl:     prefix + f.Path(),
+		Items:       make([]feed.Item, 0, len(articles)),
+	}
+	for _, article := range articles {
+		item := newItem(article, prefix)
+		if item.Updated.After(fd.Updated) {
+			fd.Updated = item.Updated
+		}
+		fd.Items = append(fd.Items, item)
+	}
+
+	body, err := fd.Render(f.Format)
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum(body)
+
+	return &Document{
+		Body:         body,
+		ContentType:  feed.ContentType(f.Format),
+		ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		LastModified: fd.Updated,
+	}, nil
+}
+
+// newItem turns a published article into a feed entry, linked to the article on the blog
+func newItem(article *models.Article, prefix string) feed.Item {
+	path := article.Slug
+	if path == "" {
+		path = strconv.Itoa(article.ID)
+	}
+	published := article.PublishAt
+	if published == 0 {
+		published = article.CreatedOn
+	}
+	updated := article.ModifiedOn
+	if updated < published {
+		updated = published
+	}
+
+	contentHtml := article.ContentHtml
+	if contentHtml == "" && article.Content != "" {
+		contentHtml, _ = article_service.RenderContent(article.ContentFormat, article.Content)
+	}
+	categories := make([]string, 0, len(article.Tags))
+	for _, tag := range article.Tags {
+		categories = append(categories, tag.Name)
+	}
+
+	return feed.Item{
+		ID:          itemID(prefix, article),
+		Title:       article.Title,
+		Link:        prefix + fmt.Sprintf(setting.AppSetting.FeedArticlePath, url.PathEscape(path)),
+		Summary:     article.Desc,
+		ContentHtml: contentHtml,
+		Author:      article.CreatedBy,
+		Categories:  categories,
+		Published:   time.Unix(int64(published), 0),
+		Updated:     time.Unix(int64(updated), 0),
+	}
+}
+
+// itemID is a tag URI (RFC 4151) of the article, which unlike its link survives changes of the slug
+func itemID(prefix string, article *models.Article) string {
+	host := prefix
+	if u, err := url.Parse(prefix); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	return fmt.Sprintf("tag:%s,%s:article:%d", host, time.Unix(int64(article.CreatedOn), 0).UTC().Format("2006-01-02"), article.ID)
+}
@@ -0,0 +1,87 @@
+package jwt_redis_service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+const (
+	FAILURE_POLICY_OPEN   = "open"
+	FAILURE_POLICY_CLOSED = "closed"
+	FAILURE_POLICY_LOCAL  = "local"
+)
+
+var ErrStoreUnavailable = errors.New("session store is unavailable")
+
+// GetFailurePolicy returns the configured behaviour for when Redis is unreachable
+func GetFailurePolicy() string {
+	switch policy := setting.AppSetting.SessionStoreFailurePolicy; policy {
+	case FAILURE_POLICY_CLOSED, FAILURE_POLICY_LOCAL:
+		return policy
+	default:
+		return FAILURE_POLICY_OPEN
+	}
+}
+
+// writeFailed decides whether a failed Redis write has to fail the caller
+func writeFailed(err error) error {
+	logging.Warn("session store write failed:", err)
+	if GetFailurePolicy() == FAILURE_POLICY_CLOSED {
+		return ErrStoreUnavailable
+	}
+
+	return nil
+}
+
+// readFailed decides whether a token whose session could not be read is still valid
+func readFailed(id string, err error) (bool, error) {
+	logging.Warn("session store read failed:", err)
+	switch GetFailurePolicy() {
+	case FAILURE_POLICY_CLOSED:
+		return false, ErrStoreUnavailable
+	case FAILURE_POLICY_LOCAL:
+		return !localRevocations.IsRevoked(id), nil
+	default:
+		return true, nil
+	}
+}
+
+// revocationList is an in-process list of revoked IDs (jti or token family),
+// used to keep revocations effective while Redis is unreachable
+type revocationList struct {
+	mu      sync.Mutex
+	entries map[string]int64
+}
+
+var localRevocations = &revocationList{entries: make(map[string]int64)}
+
+// Revoke remembers an ID as revoked until the given unix time
+func (l *revocationList) Revoke(id string, until int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now().Unix()
+	for k, v := range l.entries {
+		if v < now {
+			delete(l.entries, k)
+		}
+	}
+	l.entries[id] = until
+}
+
+// IsRevoked checks whether an ID has been revoked locally
+func (l *revocationList) IsRevoked(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until, ok := l.entries[id]
+	return ok && until >= time.Now().Unix()
+}
@@ -0,0 +1,174 @@
+package jwt_redis_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis/gredistest"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+func usePolicy(t *testing.T, policy string) {
+	old := setting.AppSetting.SessionStoreFailurePolicy
+	setting.AppSetting.SessionStoreFailurePolicy = policy
+	t.Cleanup(func() {
+		setting.AppSetting.SessionStoreFailurePolicy = old
+	})
+}
+
+func newSession(id string) *Session {
+	return &Session{
+		ID:        id,
+		Username:  "alice",
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestGetFailurePolicy(t *testing.T) {
+	tests := map[string]string{
+		"":        FAILURE_POLICY_OPEN,
+		"unknown": FAILURE_POLICY_OPEN,
+		"open":    FAILURE_POLICY_OPEN,
+		"closed":  FAILURE_POLICY_CLOSED,
+		"local":   FAILURE_POLICY_LOCAL,
+	}
+	for configured, want := range tests {
+		usePolicy(t, configured)
+		if got := GetFailurePolicy(); got != want {
+			t.Errorf("GetFailurePolicy() with %q = %q, want %q", configured, got, want)
+		}
+	}
+}
+
+func TestIsSessionValid(t *testing.T) {
+	gredistest.Use(t)
+	usePolicy(t, FAILURE_POLICY_CLOSED)
+
+	if err := StoreSession(newSession("valid-1")); err != nil {
+		t.Fatalf("StoreSession: %v", err)
+	}
+
+	tests := []struct {
+		username, id string
+		want         bool
+	}{
+		{"alice", "valid-1", true},
+		{"bob", "valid-1", false},
+		{"alice", "missing", false},
+	}
+	for _, tt := range tests {
+		valid, err := IsSessionValid(tt.username, tt.id, "")
+		if err != nil || valid != tt.want {
+			t.Errorf("IsSessionValid(%q, %q) = %v, %v, want %v", tt.username, tt.id, valid, err, tt.want)
+		}
+	}
+
+	if _, err := DeleteSession("alice", "valid-1"); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	if valid, _ := IsSessionValid("alice", "valid-1", ""); valid {
+		t.Error("IsSessionValid() = true after DeleteSession")
+	}
+}
+
+func TestStoreSessionWhileDown(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr error
+	}{
+		{FAILURE_POLICY_OPEN, nil},
+		{FAILURE_POLICY_CLOSED, ErrStoreUnavailable},
+		{FAILURE_POLICY_LOCAL, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			redis := gredistest.Use(t)
+			usePolicy(t, tt.policy)
+			redis.SetDown(true)
+
+			if err := StoreSession(newSession("store-" + tt.policy)); err != tt.wantErr {
+				t.Errorf("StoreSession() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsSessionValidWhileDown(t *testing.T) {
+	tests := []struct {
+		policy    string
+		wantValid bool
+		wantErr   error
+	}{
+		{FAILURE_POLICY_OPEN, true, nil},
+		{FAILURE_POLICY_CLOSED, false, ErrStoreUnavailable},
+		{FAILURE_POLICY_LOCAL, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			redis := gredistest.Use(t)
+			usePolicy(t, tt.policy)
+			id := "read-" + tt.policy
+			if err := StoreSession(newSession(id)); err != nil {
+				t.Fatalf("StoreSession: %v", err)
+			}
+			redis.SetDown(true)
+
+			valid, err := IsSessionValid("alice", id, "")
+			if valid != tt.wantValid || err != tt.wantErr {
+				t.Errorf("IsSessionValid() = %v, %v, want %v, %v", valid, err, tt.wantValid, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRevokeTokenWhileDown(t *testing.T) {
+	tests := []struct {
+		policy      string
+		wantErr     error
+		wantRevoked bool
+	}{
+		{FAILURE_POLICY_OPEN, ErrStoreUnavailable, false},
+		{FAILURE_POLICY_CLOSED, ErrStoreUnavailable, false},
+		{FAILURE_POLICY_LOCAL, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			redis := gredistest.Use(t)
+			usePolicy(t, tt.policy)
+			session := newSession("revoke-" + tt.policy)
+			session.Family = "family-" + tt.policy
+			if err := StoreSession(session); err != nil {
+				t.Fatalf("StoreSession: %v", err)
+			}
+			redis.SetDown(true)
+
+			if err := RevokeToken("alice", session.ID, session.Family, session.ExpiresAt); err != tt.wantErr {
+				t.Fatalf("RevokeToken() = %v, want %v", err, tt.wantErr)
+			}
+			if revoked := localRevocations.IsRevoked(session.ID); revoked != tt.wantRevoked {
+				t.Errorf("revoked locally = %v, want %v", revoked, tt.wantRevoked)
+			}
+			if !tt.wantRevoked {
+				return
+			}
+
+			// The local revocation outlives the outage, whatever Redis still holds
+			if valid, err := IsSessionValid("alice", session.ID, session.Family); valid || err != nil {
+				t.Errorf("IsSessionValid() while down = %v, %v, want false", valid, err)
+			}
+			redis.SetDown(false)
+			if valid, err := IsSessionValid("alice", session.ID, session.Family); valid || err != nil {
+				t.Errorf("IsSessionValid() after recovery = %v, %v, want false", valid, err)
+			}
+			sibling := newSession("sibling-" + tt.policy)
+			sibling.Family = session.Family
+			if err := StoreSession(sibling); err != nil {
+				t.Fatalf("StoreSession: %v", err)
+			}
+			if valid, _ := IsSessionValid("alice", sibling.ID, sibling.Family); valid {
+				t.Error("IsSessionValid() of a session in the revoked family = true")
+			}
+		})
+	}
+}
@@ -7,87 +7,214 @@ import (
 	"github.com/gomodule/redigo/redis"
 
 	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
 )
 
 const (
-	JWT_TOKEN_PREFIX = "jwt_token:"
-	JWT_TOKEN_TTL    = 3 * 60 * 60 // 3 hours in seconds
+	JWT_SESSION_PREFIX       = "jwt_session:"
+	JWT_USER_SESSIONS_PREFIX = "jwt_sessions:"
 )
 
-// StoreToken stores JWT token in Redis with expiration
-func StoreToken(username string, token string) error {
-	key := JWT_TOKEN_PREFIX + username
-	err := gredis.Set(key, token, JWT_TOKEN_TTL)
-	if err != nil {
-		// If Redis is not available, we'll just log the error but not fail
-		// This allows the system to work without Redis for development
-		// In production, you should ensure Redis is available
-		return nil // Return nil to allow JWT generation to continue
+// Session describes a single issued token, identified by its jti
+type Session struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Device    string `json:"device"`
+	IP        string `json:"ip"`
+	IssuedAt  int64  `json:"issued_at"`
+	ExpiresAt int64  `json:"expires_at"`
+
+	// Family is the refresh token family the session was issued in, if any
+	Family string `json:"family,omitempty"`
+}
+
+func getSessionKey(id string) string {
+	return JWT_SESSION_PREFIX + id
+}
+
+func getUserSessionsKey(username string) string {
+	return JWT_USER_SESSIONS_PREFIX + username
+}
+
+// StoreSession stores a session in Redis and indexes it under its user
+func StoreSession(s *Session) error {
+	ttl := getTTL(s.ExpiresAt)
+	if err := gredis.Set(getSessionKey(s.ID), s, ttl); err != nil {
+		return writeFailed(err)
 	}
+
+	key := getUserSessionsKey(s.Username)
+	if err := gredis.SAdd(key, s.ID); err != nil {
+		return writeFailed(err)
+	}
+	// The index lives as long as the newest session in it
+	gredis.Expire(key, ttl)
+
 	return nil
 }
 
-// GetToken retrieves JWT token from Redis
-func GetToken(username string) (string, error) {
-	key := JWT_TOKEN_PREFIX + username
-	data, err := gredis.Get(key)
+// GetSession retrieves a session from Redis by its ID
+func GetSession(id string) (*Session, error) {
+	data, err := gredis.Get(getSessionKey(id))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	
-	var token string
-	err = json.Unmarshal(data, &token)
+
+	var session Session
+	err = json.Unmarshal(data, &session)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	
-	return token, nil
-}
 
-// DeleteToken removes JWT token from Redis
-func DeleteToken(username string) error {
-	key := JWT_TOKEN_PREFIX + username
-	_, err := gredis.Delete(key)
-	return err
+	return &session, nil
 }
 
-// IsTokenValid checks if token exists and matches in Redis
-func IsTokenValid(username string, token string) (bool, error) {
-	storedToken, err := GetToken(username)
+// IsSessionValid checks if the session exists in Redis and belongs to the user.
+// When Redis is unreachable the configured failure policy decides.
+func IsSessionValid(username, id, family string) (bool, error) {
+	if localRevocations.IsRevoked(id) || localRevocations.IsRevoked(family) {
+		return false, nil
+	}
+
+	session, err := GetSession(id)
 	if err != nil {
 		if err == redis.ErrNil {
-			// Token not found in Redis, but we'll allow it for development
-			// when Redis is not available
-			return true, nil
+			// Session was revoked or has expired
+			return false, nil
 		}
-		// If Redis is not available, we'll allow the token (fallback mode)
-		// In production, ensure Redis is properly configured
-		return true, nil
+		return readFailed(id, err)
 	}
-	return storedToken == token, nil
+
+	return session.Username == username, nil
+}
+
+// RevokeToken revokes the session of a token presented by its owner. If Redis is
+// unreachable and the failure policy is local, the revocation is kept in process
+// until the token expires.
+func RevokeToken(username, id, family string, expiresAt int64) error {
+	_, err := DeleteSession(username, id)
+	if err == nil || GetFailurePolicy() != FAILURE_POLICY_LOCAL {
+		return err
+	}
+
+	logging.Warn("session store unavailable, revoking locally:", err)
+	localRevocations.Revoke(id, expiresAt)
+	if family != "" {
+		localRevocations.Revoke(family, time.Now().Add(setting.AppSetting.RefreshTokenExpire).Unix())
+	}
+
+	return nil
 }
 
-// RefreshToken updates the token and resets its expiration
-func RefreshToken(username string, newToken string) error {
-	return StoreToken(username, newToken)
+// ListSessions returns all live sessions of a user, pruning expired ones from the index
+func ListSessions(username string) ([]*Session, error) {
+	key := getUserSessionsKey(username)
+	ids, err := gredis.SMembers(key)
+	if err != nil {
+		return nil, ErrStoreUnavailable
+	}
+
+	sessions := make([]*Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := GetSession(id)
+		if err != nil {
+			if err == redis.ErrNil {
+				gredis.SRem(key, id)
+				continue
+			}
+			return nil, ErrStoreUnavailable
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
 }
 
-// GetTokenTTL returns the remaining time-to-live for a token
-func GetTokenTTL(username string) (time.Duration, error) {
-	conn := gredis.RedisConn.Get()
-	defer conn.Close()
+// DeleteSession revokes a single session of a user together with its refresh token family
+func DeleteSession(username, id string) (bool, error) {
+	session, err := GetSession(id)
+	if err != nil {
+		if err == redis.ErrNil {
+			return false, nil
+		}
+		return false, ErrStoreUnavailable
+	}
+	if session.Username != username {
+		return false, nil
+	}
+
+	if err := revokeSession(session); err != nil {
+		return false, ErrStoreUnavailable
+	}
+
+	return true, nil
+}
 
-	key := JWT_TOKEN_PREFIX + username
-	ttl, err := redis.Int(conn.Do("TTL", key))
+// DeleteOtherSessions revokes every session of a user except the given one
+func DeleteOtherSessions(username, currentID string) (int, error) {
+	sessions, err := ListSessions(username)
 	if err != nil {
 		return 0, err
 	}
 
-	if ttl == -1 {
-		return 0, nil // Key exists but has no expiration
-	} else if ttl == -2 {
-		return 0, redis.ErrNil // Key doesn't exist
+	var currentFamily string
+	for _, session := range sessions {
+		if session.ID == currentID {
+			currentFamily = session.Family
+		}
+	}
+
+	count := 0
+	for _, session := range sessions {
+		if session.ID == currentID {
+			continue
+		}
+		// Revoking a sibling would take the current session's family down with it
+		if currentFamily != "" && session.Family == currentFamily {
+			continue
+		}
+
+		if err := revokeSession(session); err != nil {
+			return count, ErrStoreUnavailable
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// DeleteAllSessions revokes every session of a user
+func DeleteAllSessions(username string) error {
+	_, err := DeleteOtherSessions(username, "")
+	return err
+}
+
+// revokeSession removes a session, or its whole token family if it has one
+func revokeSession(s *Session) error {
+	if s.Family != "" {
+		return RevokeFamily(s.Family)
+	}
+
+	return removeSession(s.Username, s.ID)
+}
+
+// removeSession removes a single session and its index entry
+func removeSession(username, id string) error {
+	if _, err := gredis.Delete(getSessionKey(id)); err != nil {
+		return err
+	}
+
+	return gredis.SRem(getUserSessionsKey(username), id)
+}
+
+// getTTL converts an expiry timestamp into a Redis TTL in seconds
+func getTTL(expiresAt int64) int {
+	ttl := int(expiresAt - time.Now().Unix())
+	if ttl < 1 {
+		return 1
 	}
 
-	return time.Duration(ttl) * time.Second, nil
+	return ttl
 }
\ No newline at end of file
@@ -0,0 +1,80 @@
+package jwt_redis_service
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+)
+
+const (
+	MFA_CHALLENGE_PREFIX = "mfa_challenge:"
+	TOTP_USED_PREFIX     = "totp_used:"
+)
+
+var ErrMfaChallengeInvalid = errors.New("mfa challenge is invalid or expired")
+
+// MfaChallenge is issued instead of a token pair when the password of an account with
+// two-factor authentication enabled was correct
+type MfaChallenge struct {
+	Username  string `json:"username"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+func getMfaChallengeKey(hash string) string {
+	return MFA_CHALLENGE_PREFIX + hash
+}
+
+// StoreMfaChallenge stores a challenge under the hash of its token. The second
+// login step cannot work without it, so failures are reported whatever the policy.
+func StoreMfaChallenge(hash string, ch *MfaChallenge) error {
+	if err := gredis.Set(getMfaChallengeKey(hash), ch, getTTL(ch.ExpiresAt)); err != nil {
+		logging.Warn("mfa challenge write failed:", err)
+		return ErrStoreUnavailable
+	}
+
+	return nil
+}
+
+// GetMfaChallenge retrieves a challenge by the hash of its token
+func GetMfaChallenge(hash string) (*MfaChallenge, error) {
+	data, err := gredis.Get(getMfaChallengeKey(hash))
+	if err == redis.ErrNil {
+		return nil, ErrMfaChallengeInvalid
+	}
+	if err != nil {
+		return nil, ErrStoreUnavailable
+	}
+
+	var ch MfaChallenge
+	if err := json.Unmarshal(data, &ch); err != nil {
+		return nil, err
+	}
+
+	return &ch, nil
+}
+
+// ConsumeMfaChallenge deletes a challenge, reporting whether this caller was the one to delete it
+func ConsumeMfaChallenge(hash string) (bool, error) {
+	deleted, err := gredis.Delete(getMfaChallengeKey(hash))
+	if err != nil {
+		return false, ErrStoreUnavailable
+	}
+
+	return deleted, nil
+}
+
+// MarkTOTPUsed records that a TOTP time step was used by a user, reporting false if it
+// already was, so a code cannot be replayed within its validity window
+func MarkTOTPUsed(username string, step int64, ttl int) (bool, error) {
+	ok, err := gredis.SetNX(TOTP_USED_PREFIX+username+":"+strconv.FormatInt(step, 10), 1, ttl)
+	if err != nil {
+		return false, ErrStoreUnavailable
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,99 @@
+package jwt_redis_service
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+)
+
+const (
+	PASSWORD_RESET_PREFIX      = "password_reset:"
+	PASSWORD_RESET_USER_PREFIX = "password_reset_user:"
+)
+
+var ErrPasswordResetInvalid = errors.New("password reset token is invalid or expired")
+
+// PasswordReset is the account a mailed password reset token was issued for
+type PasswordReset struct {
+	Username  string `json:"username"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+func getPasswordResetKey(hash string) string {
+	return PASSWORD_RESET_PREFIX + hash
+}
+
+func getUserPasswordResetKey(username string) string {
+	return PASSWORD_RESET_USER_PREFIX + username
+}
+
+// StorePasswordReset stores a reset under the hash of its token, replacing any earlier
+// reset of the same user so only the latest mail works. A reset that is not stored
+// cannot be used, so failures are reported whatever the policy.
+func StorePasswordReset(hash string, r *PasswordReset) error {
+	userKey := getUserPasswordResetKey(r.Username)
+	if data, err := gredis.Get(userKey); err == nil {
+		var old string
+		if json.Unmarshal(data, &old) == nil {
+			if _, err := gredis.Delete(getPasswordResetKey(old)); err != nil {
+				logging.Warn("password reset delete failed:", err)
+			}
+		}
+	}
+
+	ttl := getTTL(r.ExpiresAt)
+	if err := gredis.Set(getPasswordResetKey(hash), r, ttl); err != nil {
+		logging.Warn("password reset write failed:", err)
+		return ErrStoreUnavailable
+	}
+	if err := gredis.Set(userKey, hash, ttl); err != nil {
+		logging.Warn("password reset write failed:", err)
+	}
+
+	return nil
+}
+
+// GetPasswordReset retrieves a reset by the hash of its token without using it up
+func GetPasswordReset(hash string) (*PasswordReset, error) {
+	data, err := gredis.Get(getPasswordResetKey(hash))
+	if err == redis.ErrNil {
+		return nil, ErrPasswordResetInvalid
+	}
+	if err != nil {
+		return nil, ErrStoreUnavailable
+	}
+
+	var r PasswordReset
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// ConsumePasswordReset deletes a reset by the hash of its token and returns it, so that
+// of several concurrent callers only one gets it
+func ConsumePasswordReset(hash string) (*PasswordReset, error) {
+	r, err := GetPasswordReset(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := gredis.Delete(getPasswordResetKey(hash))
+	if err != nil {
+		return nil, ErrStoreUnavailable
+	}
+	if !deleted {
+		return nil, ErrPasswordResetInvalid
+	}
+
+	if _, err := gredis.Delete(getUserPasswordResetKey(r.Username)); err != nil {
+		logging.Warn("password reset delete failed:", err)
+	}
+
+	return r, nil
+}
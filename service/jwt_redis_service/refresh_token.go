@@ -0,0 +1,159 @@
+package jwt_redis_service
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+)
+
+const (
+	JWT_REFRESH_TOKEN_PREFIX  = "jwt_refresh:"
+	JWT_REFRESH_USED_PREFIX   = "jwt_refresh_used:"
+	JWT_REFRESH_FAMILY_PREFIX = "jwt_refresh_family:"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+// RefreshToken is the stored state of an opaque refresh token, keyed by its hash
+type RefreshToken struct {
+	Username  string `json:"username"`
+	Family    string `json:"family"`
+	SessionID string `json:"session_id"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+func getRefreshTokenKey(hash string) string {
+	return JWT_REFRESH_TOKEN_PREFIX + hash
+}
+
+func getRefreshUsedKey(hash string) string {
+	return JWT_REFRESH_USED_PREFIX + hash
+}
+
+func getFamilyKey(family string) string {
+	return JWT_REFRESH_FAMILY_PREFIX + family
+}
+
+// StoreRefreshToken stores a refresh token and registers its access session in the token family
+func StoreRefreshToken(hash string, t *RefreshToken) error {
+	ttl := getTTL(t.ExpiresAt)
+	if err := gredis.Set(getRefreshTokenKey(hash), t, ttl); err != nil {
+		// Under the open and local policies the login still succeeds, the refresh token just can't be redeemed
+		return writeFailed(err)
+	}
+
+	key := getFamilyKey(t.Family)
+	if err := gredis.SAdd(key, t.SessionID); err != nil {
+		return writeFailed(err)
+	}
+	gredis.Expire(key, ttl)
+
+	return nil
+}
+
+// GetRefreshToken retrieves a refresh token from Redis by its hash
+func GetRefreshToken(hash string) (*RefreshToken, error) {
+	data, err := gredis.Get(getRefreshTokenKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	var token RefreshToken
+	err = json.Unmarshal(data, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// GetActiveRefreshToken returns a refresh token that can still be redeemed, nil if it cannot
+func GetActiveRefreshToken(hash string) (*RefreshToken, error) {
+	token, err := GetRefreshToken(hash)
+	if err != nil {
+		if err == redis.ErrNil {
+			return nil, nil
+		}
+		return nil, ErrStoreUnavailable
+	}
+	if localRevocations.IsRevoked(token.Family) || gredis.Exists(getRefreshUsedKey(hash)) ||
+		!gredis.Exists(getFamilyKey(token.Family)) {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken consumes a refresh token exactly once.
+// Presenting an already consumed token revokes its whole family, since either
+// the legitimate client or an attacker is holding a stolen copy; the token is
+// returned along with ErrRefreshTokenReused so the revocation can be audited.
+func RotateRefreshToken(hash string) (*RefreshToken, error) {
+	token, err := GetRefreshToken(hash)
+	if err != nil {
+		if err == redis.ErrNil {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, ErrStoreUnavailable
+	}
+	if localRevocations.IsRevoked(token.Family) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	firstUse, err := gredis.SetNX(getRefreshUsedKey(hash), 1, getTTL(token.ExpiresAt))
+	if err != nil {
+		return nil, ErrStoreUnavailable
+	}
+	if !firstUse {
+		if err := RevokeFamily(token.Family); err != nil {
+			return nil, ErrStoreUnavailable
+		}
+		return token, ErrRefreshTokenReused
+	}
+
+	if !gredis.Exists(getFamilyKey(token.Family)) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// The access token paired with the consumed refresh token is superseded
+	if err := removeSession(token.Username, token.SessionID); err != nil {
+		return nil, ErrStoreUnavailable
+	}
+	if err := gredis.SRem(getFamilyKey(token.Family), token.SessionID); err != nil {
+		return nil, ErrStoreUnavailable
+	}
+
+	return token, nil
+}
+
+// RevokeFamily revokes every session issued in a refresh token family and the family itself
+func RevokeFamily(family string) error {
+	key := getFamilyKey(family)
+	ids, err := gredis.SMembers(key)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		session, err := GetSession(id)
+		if err != nil {
+			if err == redis.ErrNil {
+				continue
+			}
+			return err
+		}
+
+		if err := removeSession(session.Username, session.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = gredis.Delete(key)
+	return err
+}
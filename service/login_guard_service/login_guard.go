@@ -0,0 +1,134 @@
+package login_guard_service
+
+import (
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+const (
+	LOGIN_FAIL_USER_PREFIX = "login_fail:user:"
+	LOGIN_FAIL_IP_PREFIX   = "login_fail:ip:"
+	LOGIN_LOCK_USER_PREFIX = "login_lock:user:"
+	LOGIN_LOCK_IP_PREFIX   = "login_lock:ip:"
+)
+
+// Guard tracks failed logins of a username coming from a client IP
+type Guard struct {
+	Username string
+	IP       string
+}
+
+// Locked returns how long the username or the IP is still locked out, zero if neither is
+func (g *Guard) Locked() time.Duration {
+	userTTL := ttl(LOGIN_LOCK_USER_PREFIX + g.Username)
+	ipTTL := ttl(LOGIN_LOCK_IP_PREFIX + g.IP)
+	if ipTTL > userTTL {
+		return ipTTL
+	}
+
+	return userTTL
+}
+
+// Fail records a failed login, locking out the username and/or the IP once their
+// threshold is reached. It returns the resulting lockout, zero if there is none.
+func (g *Guard) Fail() time.Duration {
+	userLock := fail(LOGIN_FAIL_USER_PREFIX+g.Username, LOGIN_LOCK_USER_PREFIX+g.Username, setting.AppSetting.LoginMaxAttempts)
+	ipLock := fail(LOGIN_FAIL_IP_PREFIX+g.IP, LOGIN_LOCK_IP_PREFIX+g.IP, setting.AppSetting.LoginIPMaxAttempts)
+	if ipLock > userLock {
+		return ipLock
+	}
+
+	return userLock
+}
+
+// Succeed resets the failed attempts of the username after a successful login. The IP counter
+// only expires with LoginAttemptWindow, or one valid account would let a client reset it between
+// guesses at other usernames.
+func (g *Guard) Succeed() {
+	del(LOGIN_FAIL_USER_PREFIX + g.Username)
+}
+
+// Unlock lifts the lockout of the username and, if set, the IP and resets their counters
+func (g *Guard) Unlock() {
+	del(LOGIN_FAIL_USER_PREFIX+g.Username, LOGIN_LOCK_USER_PREFIX+g.Username)
+	if g.IP != "" {
+		del(LOGIN_FAIL_IP_PREFIX+g.IP, LOGIN_LOCK_IP_PREFIX+g.IP)
+	}
+}
+
+// fail increments a failure counter and locks once it reaches the threshold. Every
+// failure past the threshold doubles the lockout, up to LoginLockoutMax.
+func fail(counterKey, lockKey string, threshold int) time.Duration {
+	count := incr(counterKey, setting.AppSetting.LoginAttemptWindow)
+	if threshold <= 0 || count < threshold {
+		return 0
+	}
+
+	lockout := getLockout(count - threshold)
+	lock(lockKey, lockout)
+
+	return lockout
+}
+
+// getLockout returns the lockout after the given number of failures past the threshold
+func getLockout(over int) time.Duration {
+	lockout := setting.AppSetting.LoginLockoutBase
+	max := setting.AppSetting.LoginLockoutMax
+	for i := 0; i < over && lockout < max; i++ {
+		lockout *= 2
+	}
+	if max > 0 && lockout > max {
+		return max
+	}
+
+	return lockout
+}
+
+// incr increments a counter expiring after window, falling back to memory when Redis is down
+func incr(key string, window time.Duration) int {
+	count, err := gredis.Incr(key)
+	if err != nil {
+		logging.Warn("login guard falling back to memory:", err)
+		return localAttempts.Incr(key, window)
+	}
+	if count == 1 {
+		gredis.Expire(key, util.Seconds(window))
+	}
+
+	return count
+}
+
+func lock(key string, lockout time.Duration) {
+	if err := gredis.Set(key, 1, util.Seconds(lockout)); err != nil {
+		logging.Warn("login guard falling back to memory:", err)
+		localAttempts.Set(key, lockout)
+	}
+}
+
+func ttl(key string) time.Duration {
+	// A lock recorded in memory while Redis was down must keep applying once it is back
+	local := localAttempts.TTL(key)
+
+	secs, err := gredis.TTL(key)
+	if err != nil {
+		return local
+	}
+	if remote := time.Duration(secs) * time.Second; remote > local {
+		return remote
+	}
+
+	return local
+}
+
+func del(keys ...string) {
+	for _, key := range keys {
+		localAttempts.Delete(key)
+		if _, err := gredis.Delete(key); err != nil {
+			logging.Warn("login guard delete failed:", err)
+		}
+	}
+}
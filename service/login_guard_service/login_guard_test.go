@@ -0,0 +1,81 @@
+package login_guard_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis/gredistest"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+func setup(t *testing.T) {
+	old := *setting.AppSetting
+	setting.AppSetting.LoginMaxAttempts = 3
+	setting.AppSetting.LoginIPMaxAttempts = 5
+	setting.AppSetting.LoginAttemptWindow = 15 * time.Minute
+	setting.AppSetting.LoginLockoutBase = time.Minute
+	setting.AppSetting.LoginLockoutMax = time.Hour
+	t.Cleanup(func() {
+		*setting.AppSetting = old
+	})
+	gredistest.Use(t)
+}
+
+func TestFailLocksUsername(t *testing.T) {
+	setup(t)
+	guard := Guard{Username: "alice", IP: "10.0.0.1"}
+
+	for i := 1; i < 3; i++ {
+		if lockout := guard.Fail(); lockout != 0 {
+			t.Fatalf("Fail() #%d = %v, want no lockout", i, lockout)
+		}
+	}
+	if lockout := guard.Fail(); lockout != time.Minute {
+		t.Errorf("Fail() #3 = %v, want %v", lockout, time.Minute)
+	}
+	if lockout := guard.Fail(); lockout != 2*time.Minute {
+		t.Errorf("Fail() #4 = %v, want %v", lockout, 2*time.Minute)
+	}
+	if locked := guard.Locked(); locked <= 0 {
+		t.Errorf("Locked() = %v, want a lockout", locked)
+	}
+
+	other := Guard{Username: "bob", IP: "10.0.0.2"}
+	if locked := other.Locked(); locked != 0 {
+		t.Errorf("Locked() of another username and IP = %v, want 0", locked)
+	}
+}
+
+func TestSucceedKeepsIPCounter(t *testing.T) {
+	setup(t)
+	ip := "10.0.0.1"
+
+	// Guesses at other usernames, each followed by a login into a valid account from the same IP
+	for i := 1; i <= 4; i++ {
+		guess := Guard{Username: "victim", IP: ip}
+		guess.Fail()
+		if i < 3 {
+			valid := Guard{Username: "attacker", IP: ip}
+			valid.Succeed()
+		}
+	}
+	guess := Guard{Username: "other", IP: ip}
+	if lockout := guess.Fail(); lockout != time.Minute {
+		t.Errorf("Fail() at the IP threshold = %v, want %v", lockout, time.Minute)
+	}
+	if locked := (&Guard{Username: "attacker", IP: ip}).Locked(); locked <= 0 {
+		t.Errorf("Locked() of the IP = %v, want a lockout", locked)
+	}
+}
+
+func TestSucceedResetsUsernameCounter(t *testing.T) {
+	setup(t)
+	guard := Guard{Username: "alice", IP: "10.0.0.1"}
+
+	guard.Fail()
+	guard.Fail()
+	guard.Succeed()
+	if lockout := guard.Fail(); lockout != 0 {
+		t.Errorf("Fail() after Succeed() = %v, want no lockout", lockout)
+	}
+}
@@ -0,0 +1,78 @@
+package login_guard_service
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryCounter is one in-process counter or lock
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// memoryStore keeps counters and locks in process while Redis is unreachable
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryCounter
+}
+
+var localAttempts = &memoryStore{entries: make(map[string]*memoryCounter)}
+
+// Incr increments a counter, starting a new window if it has expired
+func (s *memoryStore) Incr(key string, window time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryCounter{expiresAt: time.Now().Add(window)}
+		s.entries[key] = entry
+	}
+	entry.count++
+
+	return entry.count
+}
+
+// Set stores a lock for the given duration
+func (s *memoryStore) Set(key string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	s.entries[key] = &memoryCounter{count: 1, expiresAt: time.Now().Add(d)}
+}
+
+// TTL returns the remaining lifetime of an entry, zero if it does not exist
+func (s *memoryStore) TTL(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return 0
+	}
+	if ttl := time.Until(entry.expiresAt); ttl > 0 {
+		return ttl
+	}
+
+	return 0
+}
+
+// Delete removes an entry
+func (s *memoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+func (s *memoryStore) prune() {
+	now := time.Now()
+	for k, v := range s.entries {
+		if v.expiresAt.Before(now) {
+			delete(s.entries, k)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package login_guard_service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+const (
+	PASSWORD_RESET_EMAIL_PREFIX = "password_reset_requests:email:"
+	PASSWORD_RESET_IP_PREFIX    = "password_reset_requests:ip:"
+)
+
+// ResetThrottle limits the password reset mails requested for an email and from a client IP
+type ResetThrottle struct {
+	Email string
+	IP    string
+}
+
+// Request counts a reset request. It returns how long to wait once the email or the IP
+// is over its allowance for PasswordResetRequestWindow, zero if the request may proceed.
+func (r *ResetThrottle) Request() time.Duration {
+	window := setting.AppSetting.PasswordResetRequestWindow
+	emailWait := request(PASSWORD_RESET_EMAIL_PREFIX+strings.ToLower(r.Email), setting.AppSetting.PasswordResetMaxRequests, window)
+	ipWait := request(PASSWORD_RESET_IP_PREFIX+r.IP, setting.AppSetting.PasswordResetIPMaxRequests, window)
+	if ipWait > emailWait {
+		return ipWait
+	}
+
+	return emailWait
+}
+
+// request increments a request counter, returning the rest of its window once it is over max
+func request(key string, max int, window time.Duration) time.Duration {
+	count := incr(key, window)
+	if max <= 0 || count <= max {
+		return 0
+	}
+	if wait := ttl(key); wait > 0 {
+		return wait
+	}
+
+	return window
+}
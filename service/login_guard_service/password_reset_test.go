@@ -0,0 +1,53 @@
+package login_guard_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+func setupResetThrottle(t *testing.T) {
+	setup(t)
+	setting.AppSetting.PasswordResetMaxRequests = 2
+	setting.AppSetting.PasswordResetIPMaxRequests = 3
+	setting.AppSetting.PasswordResetRequestWindow = time.Hour
+}
+
+func TestResetThrottleLimitsEmail(t *testing.T) {
+	setupResetThrottle(t)
+
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		throttle := ResetThrottle{Email: "alice@example.com", IP: ip}
+		if wait := throttle.Request(); wait != 0 {
+			t.Fatalf("Request() #%d = %v, want no wait", i+1, wait)
+		}
+	}
+
+	// The email is matched case-insensitively, as accounts are looked up
+	throttle := ResetThrottle{Email: "Alice@Example.com", IP: "10.0.0.9"}
+	if wait := throttle.Request(); wait <= 0 || wait > time.Hour {
+		t.Errorf("Request() #3 = %v, want a wait of at most %v", wait, time.Hour)
+	}
+
+	other := ResetThrottle{Email: "bob@example.com", IP: "10.0.0.9"}
+	if wait := other.Request(); wait != 0 {
+		t.Errorf("Request() for another email = %v, want no wait", wait)
+	}
+}
+
+func TestResetThrottleLimitsIP(t *testing.T) {
+	setupResetThrottle(t)
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"}
+
+	for i, email := range emails {
+		throttle := ResetThrottle{Email: email, IP: "10.0.0.1"}
+		wait := throttle.Request()
+		if i < 3 && wait != 0 {
+			t.Errorf("Request() #%d = %v, want no wait", i+1, wait)
+		}
+		if i == 3 && wait <= 0 {
+			t.Errorf("Request() #%d = %v, want a wait", i+1, wait)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package oauth_client_service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+	"github.com/EDDYCJY/go-gin-example/service/jwt_redis_service"
+)
+
+var (
+	ErrClientInvalid = errors.New("oauth client authentication failed")
+	ErrScopeInvalid  = errors.New("requested scope is not allowed for the client")
+)
+
+type OauthClient struct {
+	ID        int
+	Name      string
+	Scopes    []string
+	CreatedBy string
+}
+
+// Add registers the client, returning its client_id and its secret, which is only available now
+func (o *OauthClient) Add() (string, string, error) {
+	clientID, err := randomHex(12)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = models.AddOauthClient(map[string]interface{}{
+		"client_id":   clientID,
+		"secret_hash": util.EncodeSHA256(secret),
+		"name":        o.Name,
+		"scopes":      strings.Join(o.Scopes, ","),
+		"state":       1,
+		"created_by":  o.CreatedBy,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return clientID, secret, nil
+}
+
+func (o *OauthClient) GetAll() ([]*models.OauthClient, error) {
+	return models.GetOauthClients()
+}
+
+func (o *OauthClient) ExistByID() (bool, error) {
+	return models.ExistOauthClientByID(o.ID)
+}
+
+// Delete removes the client and revokes the tokens issued to it
+func (o *OauthClient) Delete() error {
+	client, err := models.GetOauthClient(o.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := models.DeleteOauthClient(o.ID); err != nil {
+		return err
+	}
+
+	return jwt_redis_service.DeleteAllSessions(util.GetClientSubject(client.ClientID))
+}
+
+// Authenticate checks the credentials of an enabled client
+func Authenticate(clientID, secret string) (*models.OauthClient, error) {
+	if clientID == "" || secret == "" {
+		return nil, ErrClientInvalid
+	}
+
+	client, err := models.GetOauthClientByClientID(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.ID == 0 || client.State != 1 {
+		return nil, ErrClientInvalid
+	}
+	if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(util.EncodeSHA256(secret))) != 1 {
+		return nil, ErrClientInvalid
+	}
+
+	return client, nil
+}
+
+// GetScopes splits the allowed scopes of a client
+func GetScopes(client *models.OauthClient) []string {
+	if client.Scopes == "" {
+		return nil
+	}
+
+	return strings.Split(client.Scopes, ",")
+}
+
+// GrantScopes resolves the space separated scope parameter of a token request, an empty
+// request being granted every allowed scope of the client (RFC 6749 section 3.3)
+func GrantScopes(client *models.OauthClient, requested string) ([]string, error) {
+	allowed := GetScopes(client)
+	if strings.TrimSpace(requested) == "" {
+		return allowed, nil
+	}
+
+	var scopes []string
+	for _, scope := range strings.Fields(requested) {
+		ok := false
+		for _, a := range allowed {
+			if a == scope {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return nil, ErrScopeInvalid
+		}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,121 @@
+package search_service
+
+import (
+	"log"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/sanitize"
+	"github.com/EDDYCJY/go-gin-example/pkg/search"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// INDEX_BATCH_SIZE is the number of articles loaded at a time to build the memory index
+const INDEX_BATCH_SIZE = 500
+
+var engine search.Engine
+
+// Setup Initialize the search engine chosen by SearchEngine, building the memory index from the articles.
+// Articles written before content_text existed get it first, both engines search it.
+func Setup() {
+	if err := backfill(); err != nil {
+		log.Fatalf("search_service.Setup, fail to fill content_text: %v", err)
+	}
+
+	switch setting.AppSetting.SearchEngine {
+	case "", "mysql":
+		engine = mysqlEngine{}
+	case "memory":
+		index := search.NewIndex()
+		if err := build(index); err != nil {
+			log.Fatalf("search_service.Setup, fail to build the search index: %v", err)
+		}
+		engine = index
+	default:
+		log.Fatalf("search_service.Setup, unknown SearchEngine: %s", setting.AppSetting.SearchEngine)
+	}
+}
+
+// SetEngine replaces the search engine, e.g. with an Index filled without a database
+func SetEngine(e search.Engine) {
+	engine = e
+}
+
+// Search returns up to limit articles containing every term, best match first
+func Search(terms []string, limit int) ([]search.Hit, error) {
+	return engine.Search(terms, limit)
+}
+
+// Index updates the text of an article after it was added or edited. Failures are only logged,
+// the search results then miss the change.
+func Index(doc search.Document) {
+	if err := engine.Index(doc); err != nil {
+		logging.Warn("indexing the article failed:", doc.ID, err)
+	}
+}
+
+// Remove drops an article that was permanently deleted
+func Remove(id int) {
+	if err := engine.Remove(id); err != nil {
+		logging.Warn("removing the article from the search index failed:", id, err)
+	}
+}
+
+func build(index *search.Index) error {
+	afterID := 0
+	for {
+		docs, err := models.GetArticleDocuments(afterID, INDEX_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err := index.Index(doc); err != nil {
+				return err
+			}
+			afterID = doc.ID
+		}
+		if len(docs) < INDEX_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// backfill stores the text of the content of articles without content_text. Their content_html
+// is empty unless they were edited since content formats were introduced, then they are plain text.
+func backfill() error {
+	afterID := 0
+	for {
+		articles, err := models.GetArticlesWithoutContentText(afterID, INDEX_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+		for _, article := range articles {
+			text := article.Content
+			if article.ContentHtml != "" {
+				text = sanitize.Text(article.ContentHtml)
+			}
+			if err := models.EditArticleContentText(article.ID, text); err != nil {
+				return err
+			}
+			afterID = article.ID
+		}
+		if len(articles) < INDEX_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// mysqlEngine searches with the FULLTEXT index, which MySQL keeps up to date by itself
+type mysqlEngine struct{}
+
+func (mysqlEngine) Index(doc search.Document) error {
+	return nil
+}
+
+func (mysqlEngine) Remove(id int) error {
+	return nil
+}
+
+func (mysqlEngine) Search(terms []string, limit int) ([]search.Hit, error) {
+	return models.SearchArticles(terms, limit)
+}
@@ -37,6 +37,11 @@ func (t *Tag) ExistByID() (bool, error) {
 	return models.ExistTagByID(t.ID)
 }
 
+// ExistByIDs checks that every one of the IDs is a live tag
+func ExistByIDs(ids []int) (bool, error) {
+	return models.ExistTagsByIDs(ids)
+}
+
 func (t *Tag) Add() error {
 	return models.AddTag(t.Name, t.State, t.CreatedBy)
 }
@@ -50,11 +55,29 @@ func (t *Tag) Edit() error {
 		data["state"] = t.State
 	}
 
-	return models.EditTag(t.ID, data)
+	if err := models.EditTag(t.ID, data); err != nil {
+		return err
+	}
+
+	clearFeedCache()
+	return nil
 }
 
 func (t *Tag) Delete() error {
-	return models.DeleteTag(t.ID)
+	if err := models.DeleteTag(t.ID); err != nil {
+		return err
+	}
+
+	clearFeedCache()
+	return nil
+}
+
+// clearFeedCache drops the cached feeds, which carry the names of the tags and list their articles
+func clearFeedCache() {
+	feeds := cache_service.Feed{}
+	if err := gredis.LikeDeletes(feeds.GetFeedsPrefix()); err != nil {
+		logging.Warn("feed cache invalidation failed:", err)
+	}
 }
 
 func (t *Tag) Count() (int, error) {
@@ -161,7 +184,8 @@ func (t *Tag) Import(r io.Reader) error {
 				data = append(data, cell)
 			}
 
-			models.AddTag(data[1], 1, data[2])
+			// The creator column of the sheet is ignored, imported tags belong to the importer
+			models.AddTag(data[1], 1, t.CreatedBy)
 		}
 	}
 
@@ -0,0 +1,46 @@
+package tag_service
+
+import (
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/e"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// GetTrash returns a page of the deleted tags, latest deleted first
+func (t *Tag) GetTrash() ([]models.Tag, error) {
+	return models.GetDeletedTags(t.PageNum, t.PageSize)
+}
+
+func (t *Tag) CountTrash() (int, error) {
+	return models.GetDeletedTagTotal()
+}
+
+// GetFromTrash returns the deleted tag, with ID 0 if it is not in the trash
+func (t *Tag) GetFromTrash() (*models.Tag, error) {
+	return models.GetDeletedTag(t.ID)
+}
+
+// Undelete restores the tag from the trash
+func (t *Tag) Undelete() error {
+	if err := models.RestoreTag(t.ID); err != nil {
+		return err
+	}
+
+	if err := gredis.LikeDeletes(e.CACHE_TAG + "_LIST"); err != nil {
+		logging.Warn("tag cache invalidation failed:", err)
+	}
+	return nil
+}
+
+// Purge permanently deletes the tag from the trash
+func (t *Tag) Purge() error {
+	articleIDs, err := models.PurgeTag(t.ID)
+	if err != nil {
+		return err
+	}
+
+	article_service.ClearCaches(articleIDs)
+	return nil
+}
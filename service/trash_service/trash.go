@@ -0,0 +1,45 @@
+package trash_service
+
+import (
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/service/article_service"
+)
+
+// Purge permanently deletes the articles and tags that have been in the trash longer than retention
+func Purge(retention time.Duration) error {
+	before := int(time.Now().Add(-retention).Unix())
+	ids, err := models.CleanAllArticle(before)
+	if err != nil {
+		return err
+	}
+	article_service.Forget(ids)
+
+	_, articleIDs, err := models.CleanAllTag(before)
+	if err != nil {
+		return err
+	}
+	article_service.ClearCaches(articleIDs)
+
+	return nil
+}
+
+// StartRetentionJob empties the trash of expired items in the background every interval,
+// a retention of 0 keeps deleted items until they are purged by hand
+func StartRetentionJob(retention, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			if err := Purge(retention); err != nil {
+				logging.Warn("purging the trash failed:", err)
+			}
+
+			time.Sleep(interval)
+		}
+	}()
+}
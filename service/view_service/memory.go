@@ -0,0 +1,121 @@
+package view_service
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+)
+
+// memoryStore counts views in process while Redis is unreachable
+type memoryStore struct {
+	mu sync.Mutex
+	// seen holds when the dedup window of a client on an article ends
+	seen    map[string]time.Time
+	pending map[int]int
+	days    map[string]map[int]int
+}
+
+var localViews = &memoryStore{
+	seen:    make(map[string]time.Time),
+	pending: make(map[int]int),
+	days:    make(map[string]map[int]int),
+}
+
+// Record counts a view unless the client viewed the article within window
+func (s *memoryStore) Record(articleID int, client string, window time.Duration, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(now)
+	key := strconv.Itoa(articleID) + ":" + client
+	if until, ok := s.seen[key]; ok && until.After(now) {
+		return
+	}
+	s.seen[key] = now.Add(window)
+	s.count(articleID, now)
+}
+
+// Count counts a view that was already deduplicated
+func (s *memoryStore) Count(articleID int, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count(articleID, now)
+}
+
+// TakePending returns the views counted since the last call and resets them
+func (s *memoryStore) TakePending() map[int]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := s.pending
+	s.pending = make(map[int]int)
+
+	return pending
+}
+
+// Restore puts back views that could not be flushed
+func (s *memoryStore) Restore(views map[int]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, n := range views {
+		s.pending[id] += n
+	}
+}
+
+// Top returns up to limit articles with their views summed over the days, most viewed first
+func (s *memoryStore) Top(days []string, limit int) []models.ArticleViews {
+	s.mu.Lock()
+	totals := make(map[int]int)
+	for _, d := range days {
+		for id, n := range s.days[d] {
+			totals[id] += n
+		}
+	}
+	s.mu.Unlock()
+
+	views := make([]models.ArticleViews, 0, len(totals))
+	for id, n := range totals {
+		views = append(views, models.ArticleViews{ArticleID: id, Views: n})
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Views != views[j].Views {
+			return views[i].Views > views[j].Views
+		}
+		return views[i].ArticleID > views[j].ArticleID
+	})
+	if len(views) > limit {
+		views = views[:limit]
+	}
+
+	return views
+}
+
+func (s *memoryStore) count(articleID int, now time.Time) {
+	s.pending[articleID]++
+
+	d := day(now)
+	if s.days[d] == nil {
+		s.days[d] = make(map[int]int)
+	}
+	s.days[d][articleID]++
+}
+
+func (s *memoryStore) prune(now time.Time) {
+	for k, until := range s.seen {
+		if !until.After(now) {
+			delete(s.seen, k)
+		}
+	}
+
+	oldest := day(now.Add(-DAY_RETENTION))
+	for d := range s.days {
+		if d < oldest {
+			delete(s.days, d)
+		}
+	}
+}
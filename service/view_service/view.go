@@ -0,0 +1,305 @@
+package view_service
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/models"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/logging"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+	"github.com/EDDYCJY/go-gin-example/pkg/util"
+)
+
+const (
+	VIEW_SEEN_PREFIX     = "article_view:seen:"
+	VIEW_PENDING_KEY     = "article_view:pending"
+	VIEW_FLUSHING_PREFIX = "article_view:flushing:"
+	VIEW_DAY_PREFIX      = "article_view:day:"
+	VIEW_WEEK_KEY        = "article_view:week"
+	VIEW_ALL_KEY         = "article_view:all"
+)
+
+// Periods of the rankings
+const (
+	PERIOD_DAY  = "day"
+	PERIOD_WEEK = "week"
+	PERIOD_ALL  = "all"
+)
+
+const (
+	// DAY_RETENTION is how long the ranking of a day is kept, long enough for the weekly ranking
+	DAY_RETENTION = 8 * 24 * time.Hour
+	// WEEK_CACHE_TTL is how long the weekly ranking is reused before it is summed up again, in seconds
+	WEEK_CACHE_TTL = 300
+	// FLUSHING_TIMEOUT is how long counters moved aside are left to their flush, after that its
+	// process is taken for dead and another flush writes them
+	FLUSHING_TIMEOUT = 10 * time.Minute
+)
+
+// addArticleViews writes flushed views to the database, tests replace it
+var addArticleViews = models.AddArticleViews
+
+// IsValidPeriod checks whether a ranking period is known
+func IsValidPeriod(period string) bool {
+	return period == PERIOD_DAY || period == PERIOD_WEEK || period == PERIOD_ALL
+}
+
+// Setup fills the all-time ranking from the view counts of the articles and the views not
+// flushed yet, so that it survives a loss of the Redis data. Without Redis it is skipped.
+func Setup() {
+	views, err := models.GetMostViewedArticles(0)
+	if err != nil {
+		logging.Warn("loading the view counts failed:", err)
+		return
+	}
+
+	scores := make(map[string]int, len(views))
+	for _, v := range views {
+		scores[strconv.Itoa(v.ArticleID)] = v.Views
+	}
+	flushing, _ := gredis.Keys(VIEW_FLUSHING_PREFIX + "*")
+	for _, key := range append(flushing, VIEW_PENDING_KEY) {
+		if pending, err := gredis.HGetAllInts(key); err == nil {
+			for id, n := range pending {
+				scores[id] += n
+			}
+		}
+	}
+
+	if err := gredis.ZAdd(VIEW_ALL_KEY, scores); err != nil {
+		logging.Warn("building the all-time ranking failed:", err)
+	}
+}
+
+// Record counts a view of an article by a client, unless the client viewed it within
+// ViewDedupWindow. It falls back to memory while Redis is unreachable.
+func Record(articleID int, client string) {
+	id := strconv.Itoa(articleID)
+	now := time.Now()
+
+	first, err := gredis.SetNX(VIEW_SEEN_PREFIX+id+":"+client, 1, util.Seconds(setting.AppSetting.ViewDedupWindow))
+	if err != nil {
+		logging.Warn("view counting falling back to memory:", err)
+		localViews.Record(articleID, client, setting.AppSetting.ViewDedupWindow, now)
+		return
+	}
+	if !first {
+		return
+	}
+
+	dayKey := VIEW_DAY_PREFIX + day(now)
+	err = gredis.HIncrBy(VIEW_PENDING_KEY, id, 1)
+	if err == nil {
+		err = gredis.ZIncrBy(dayKey, 1, id)
+	}
+	if err == nil {
+		err = gredis.Expire(dayKey, util.Seconds(DAY_RETENTION))
+	}
+	if err == nil {
+		err = gredis.ZIncrBy(VIEW_ALL_KEY, 1, id)
+	}
+	if err != nil {
+		logging.Warn("view counting falling back to memory:", err)
+		localViews.Count(articleID, now)
+	}
+}
+
+// Flush adds the views counted since the last flush to the view_count of the articles,
+// returning the number of views written
+func Flush() (int, error) {
+	views := localViews.TakePending()
+	if err := addArticleViews(views); err != nil {
+		localViews.Restore(views)
+		return 0, err
+	}
+	total := sum(views)
+
+	// Take over the counters of flushes that never finished, their process died
+	stale, err := staleFlushingKeys(time.Now())
+	if err != nil {
+		return total, err
+	}
+	for _, key := range stale {
+		claimed := newFlushingKey()
+		if err := gredis.Rename(key, claimed); err != nil {
+			// Another process took it over first
+			continue
+		}
+		n, err := flushKey(claimed)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+
+	// Move the counters aside first, views counted meanwhile go to a new hash
+	if !gredis.Exists(VIEW_PENDING_KEY) {
+		return total, nil
+	}
+	flushing := newFlushingKey()
+	if err := gredis.Rename(VIEW_PENDING_KEY, flushing); err != nil {
+		return total, err
+	}
+	n, err := flushKey(flushing)
+
+	return total + n, err
+}
+
+// flushKey adds the views of counters moved aside to the articles and deletes them. Views that
+// cannot be written are moved back to the pending views, for the next flush.
+func flushKey(key string) (int, error) {
+	pending, err := gredis.HGetAllInts(key)
+	if err != nil {
+		return 0, err
+	}
+	views := make(map[int]int, len(pending))
+	for field, n := range pending {
+		if id, err := strconv.Atoi(field); err == nil && n > 0 {
+			views[id] = n
+		}
+	}
+
+	if err := addArticleViews(views); err != nil {
+		// Field by field, whatever is left after a failure is taken over once the key is stale
+		for field, n := range pending {
+			if gredis.HIncrBy(VIEW_PENDING_KEY, field, n) != nil || gredis.HDel(key, field) != nil {
+				break
+			}
+		}
+		return 0, err
+	}
+	if _, err := gredis.Delete(key); err != nil {
+		logging.Warn("deleting the flushed view counters failed:", err)
+	}
+
+	return sum(views), nil
+}
+
+// newFlushingKey names a hash for counters moved aside, unique to the process and the time
+func newFlushingKey() string {
+	host, _ := os.Hostname()
+	return VIEW_FLUSHING_PREFIX + host + ":" + strconv.Itoa(os.Getpid()) + ":" + strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// staleFlushingKeys returns the hashes of counters moved aside more than FLUSHING_TIMEOUT ago
+func staleFlushingKeys(now time.Time) ([]string, error) {
+	keys, err := gredis.Keys(VIEW_FLUSHING_PREFIX + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, key := range keys {
+		nanos, err := strconv.ParseInt(key[strings.LastIndex(key, ":")+1:], 10, 64)
+		if err != nil || now.Sub(time.Unix(0, nanos)) >= FLUSHING_TIMEOUT {
+			stale = append(stale, key)
+		}
+	}
+
+	return stale, nil
+}
+
+// StartFlushJob flushes the view counters in the background every interval
+func StartFlushJob(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			if _, err := Flush(); err != nil {
+				logging.Warn("flushing the view counters failed:", err)
+			}
+		}
+	}()
+}
+
+// Top returns up to limit articles with their views in the period, most viewed first. Articles
+// in the trash or not published may be among them. While Redis is unreachable the daily and
+// weekly rankings only hold the views counted in memory and the all-time ranking the flushed views.
+func Top(period string, limit int) ([]models.ArticleViews, error) {
+	now := time.Now()
+
+	key := VIEW_ALL_KEY
+	switch period {
+	case PERIOD_DAY:
+		key = VIEW_DAY_PREFIX + day(now)
+	case PERIOD_WEEK:
+		key = VIEW_WEEK_KEY
+		if err := sumWeek(now); err != nil {
+			logging.Warn("view ranking falling back to memory:", err)
+			return localViews.Top(lastDays(now, 7), limit), nil
+		}
+	}
+
+	reply, err := gredis.ZRevRangeWithScores(key, 0, limit-1)
+	if err != nil {
+		logging.Warn("view ranking falling back to memory:", err)
+		switch period {
+		case PERIOD_DAY:
+			return localViews.Top(lastDays(now, 1), limit), nil
+		case PERIOD_WEEK:
+			return localViews.Top(lastDays(now, 7), limit), nil
+		default:
+			return models.GetMostViewedArticles(limit)
+		}
+	}
+
+	views := make([]models.ArticleViews, 0, len(reply)/2)
+	for i := 0; i+1 < len(reply); i += 2 {
+		id, err := strconv.Atoi(reply[i])
+		if err != nil {
+			continue
+		}
+		score, _ := strconv.ParseFloat(reply[i+1], 64)
+		views = append(views, models.ArticleViews{ArticleID: id, Views: int(score)})
+	}
+
+	return views, nil
+}
+
+// sumWeek stores the sum of the rankings of the last seven days as the weekly ranking, unless a recent one exists
+func sumWeek(now time.Time) error {
+	if gredis.Exists(VIEW_WEEK_KEY) {
+		return nil
+	}
+
+	var keys []string
+	for _, d := range lastDays(now, 7) {
+		keys = append(keys, VIEW_DAY_PREFIX+d)
+	}
+	if err := gredis.ZUnionStore(VIEW_WEEK_KEY, keys...); err != nil {
+		return err
+	}
+
+	return gredis.Expire(VIEW_WEEK_KEY, WEEK_CACHE_TTL)
+}
+
+// day is the key of the ranking of the day of t
+func day(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// lastDays returns the keys of the n days up to the day of t
+func lastDays(t time.Time, n int) []string {
+	days := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		days = append(days, day(t.AddDate(0, 0, -i)))
+	}
+
+	return days
+}
+
+func sum(views map[int]int) int {
+	total := 0
+	for _, n := range views {
+		total += n
+	}
+
+	return total
+}
@@ -0,0 +1,115 @@
+package view_service
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis"
+	"github.com/EDDYCJY/go-gin-example/pkg/gredis/gredistest"
+	"github.com/EDDYCJY/go-gin-example/pkg/setting"
+)
+
+// database stands in for the view_count column of the articles
+type database struct {
+	views map[int]int
+	down  bool
+}
+
+func setup(t *testing.T) (*gredistest.Server, *database) {
+	old := *setting.AppSetting
+	setting.AppSetting.ViewDedupWindow = time.Hour
+	t.Cleanup(func() {
+		*setting.AppSetting = old
+	})
+
+	db := &database{views: make(map[int]int)}
+	oldAdd := addArticleViews
+	addArticleViews = func(views map[int]int) error {
+		if db.down && len(views) > 0 {
+			return errors.New("database down")
+		}
+		for id, n := range views {
+			db.views[id] += n
+		}
+		return nil
+	}
+	t.Cleanup(func() {
+		addArticleViews = oldAdd
+	})
+
+	return gredistest.Use(t), db
+}
+
+// flushingKey names counters moved aside by another process at a time
+func flushingKey(at time.Time) string {
+	return VIEW_FLUSHING_PREFIX + "other:1:" + strconv.FormatInt(at.UnixNano(), 10)
+}
+
+func TestFlush(t *testing.T) {
+	redis, db := setup(t)
+	Record(1, "alice")
+	Record(1, "alice")
+	Record(1, "bob")
+	Record(2, "alice")
+
+	if n, err := Flush(); n != 3 || err != nil {
+		t.Fatalf("Flush() = %d, %v, want 3", n, err)
+	}
+	if want := map[int]int{1: 2, 2: 1}; !reflect.DeepEqual(db.views, want) {
+		t.Errorf("view counts = %v, want %v", db.views, want)
+	}
+	if keys := redis.Keys("article_view:*ing*"); keys != nil {
+		t.Errorf("counters left after the flush: %v", keys)
+	}
+	if n, err := Flush(); n != 0 || err != nil {
+		t.Errorf("Flush() again = %d, %v, want 0", n, err)
+	}
+}
+
+func TestFlushFailureKeepsViews(t *testing.T) {
+	redis, db := setup(t)
+	Record(1, "alice")
+	Record(2, "alice")
+
+	db.down = true
+	if _, err := Flush(); err == nil {
+		t.Fatal("Flush() with the database down succeeded")
+	}
+	if keys := redis.Keys(VIEW_FLUSHING_PREFIX + "*"); keys != nil {
+		t.Errorf("counters left aside after the failed flush: %v", keys)
+	}
+	Record(1, "bob")
+
+	db.down = false
+	if n, err := Flush(); n != 3 || err != nil {
+		t.Fatalf("Flush() after recovery = %d, %v, want 3", n, err)
+	}
+	if want := map[int]int{1: 2, 2: 1}; !reflect.DeepEqual(db.views, want) {
+		t.Errorf("view counts = %v, want %v", db.views, want)
+	}
+}
+
+func TestFlushTakesOverStaleCounters(t *testing.T) {
+	redis, db := setup(t)
+	stale := flushingKey(time.Now().Add(-FLUSHING_TIMEOUT - time.Minute))
+	inProgress := flushingKey(time.Now())
+	if err := gredis.HIncrBy(stale, "1", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := gredis.HIncrBy(inProgress, "2", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := Flush(); n != 5 || err != nil {
+		t.Fatalf("Flush() = %d, %v, want 5", n, err)
+	}
+	if want := map[int]int{1: 5}; !reflect.DeepEqual(db.views, want) {
+		t.Errorf("view counts = %v, want %v", db.views, want)
+	}
+	if keys := redis.Keys(VIEW_FLUSHING_PREFIX + "*"); !reflect.DeepEqual(keys, []string{inProgress}) {
+		t.Errorf("counters aside = %v, want only the flush in progress", keys)
+	}
+}